@@ -0,0 +1,156 @@
+// Command openapigen generates Go sources from the Connect API's OpenAPI
+// document: the models, the parameters and definition of the tool for every
+// operation, the request body schemas those tools declare, and the list of
+// operation tools the registry serves. It is run by go generate from the
+// module root:
+//
+//	go run ./cmd/openapigen -spec ../openapi.yaml
+//
+// Handlers and the exported tool constructors are written by hand next to
+// the generated files.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/1password-connect/mcp-server/openapi"
+)
+
+func main() {
+	specPath := flag.String("spec", "../openapi.yaml", "Path to the OpenAPI document")
+	outDir := flag.String("out", ".", "Module root to write generated files under")
+	flag.Parse()
+
+	spec, err := openapi.Load(*specPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	g := &generator{spec: spec, source: filepath.Base(*specPath), overlay: connectOverlay}
+	files, err := g.generate()
+	if err != nil {
+		log.Fatal(err)
+	}
+	for name, src := range files {
+		dest := filepath.Join(*outDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+			log.Fatal(err)
+		}
+		if err := os.WriteFile(dest, src, 0o644); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+type generator struct {
+	spec    *openapi.Spec
+	source  string // Name of the document, for the generated header
+	overlay overlay
+}
+
+// generate returns the generated files by slash-separated path relative to
+// the module root.
+func (g *generator) generate() (map[string][]byte, error) {
+	ops, err := g.spec.Operations()
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string][]byte)
+	add := func(name string, src []byte, err error) error {
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		if src == nil {
+			return nil
+		}
+		formatted, err := format.Source(src)
+		if err != nil {
+			return fmt.Errorf("%s: %w\n%s", name, err, src)
+		}
+		files[name] = formatted
+		return nil
+	}
+
+	src, err := g.generateModels()
+	if err := add("models/models_gen.go", src, err); err != nil {
+		return nil, err
+	}
+	byPackage := make(map[string][]openapi.Operation)
+	for _, op := range ops {
+		if op.Tag == "" {
+			return nil, fmt.Errorf("%s has no tag to group it by", op.ID)
+		}
+		dir := packageDir(op)
+		byPackage[dir] = append(byPackage[dir], op)
+		src, err := g.generateOperation(op)
+		if err := add(path.Join(dir, strings.ToLower(op.ID)+"_gen.go"), src, err); err != nil {
+			return nil, err
+		}
+	}
+	for dir, ops := range byPackage {
+		src, err := g.generateSchemas(ops)
+		if err := add(path.Join(dir, "schemas_gen.go"), src, err); err != nil {
+			return nil, err
+		}
+	}
+	src, err = g.generateRegistry(ops)
+	if err := add("registry_gen.go", src, err); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// packageDir is the directory of the tools package an operation belongs to,
+// named after its tag.
+func packageDir(op openapi.Operation) string {
+	return "tools/" + strings.ToLower(op.Tag)
+}
+
+func (g *generator) header(buf *bytes.Buffer, pkg string) {
+	fmt.Fprintf(buf, "// Code generated by openapigen from %s. DO NOT EDIT.\n\npackage %s\n", g.source, pkg)
+}
+
+// generateRegistry writes the list of operation tools GetAll serves.
+func (g *generator) generateRegistry(ops []openapi.Operation) ([]byte, error) {
+	var dirs []string
+	for _, op := range ops {
+		if dir := packageDir(op); !slices.Contains(dirs, dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+	slices.Sort(dirs)
+
+	var buf bytes.Buffer
+	g.header(&buf, "main")
+	buf.WriteString("\nimport (\n")
+	fmt.Fprintf(&buf, "\t%q\n\t%q\n", g.overlay.Module+"/config", g.overlay.Module+"/models")
+	for _, dir := range dirs {
+		fmt.Fprintf(&buf, "\t%s %q\n", importName(dir), g.overlay.Module+"/"+dir)
+	}
+	buf.WriteString(")\n")
+	buf.WriteString("\n// operationTools returns the tool for every operation in the OpenAPI document.\n")
+	buf.WriteString("func operationTools(cfg *config.APIConfig) []models.Tool {\n\treturn []models.Tool{\n")
+	for _, op := range ops {
+		fmt.Fprintf(&buf, "\t\t%s.%s(cfg),\n", importName(packageDir(op)), createFunc(op.ID))
+	}
+	buf.WriteString("\t}\n}\n")
+	return buf.Bytes(), nil
+}
+
+// importName is the name registry.go imports a tools package under.
+func importName(dir string) string {
+	return strings.ReplaceAll(dir, "/", "_")
+}
+
+// rawString quotes s as a Go raw string literal, splicing in any backquotes.
+func rawString(s string) string {
+	return "`" + strings.ReplaceAll(s, "`", "` + \"`\" + `") + "`"
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/1password-connect/mcp-server/openapi"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata/golden")
+
+var petstoreOverlay = overlay{
+	Module:       "example.com/petstore",
+	Titles:       map[string]string{"ListPets": "List pets"},
+	Descriptions: map[string]string{"GetStatus": "Report whether the store is open."},
+	Resolvable:   []string{"petId"},
+	Filled:       map[string][]string{"CreatePet": {"owner"}},
+	Elements:     map[string]string{"PetPatch": "PetPatchOperation"},
+}
+
+// TestGolden generates code for testdata/petstore.yaml and compares it with
+// the files in testdata/golden. Run with -update after changing the
+// generator on purpose.
+func TestGolden(t *testing.T) {
+	spec, err := openapi.Load(filepath.Join("testdata", "petstore.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := &generator{spec: spec, source: "petstore.yaml", overlay: petstoreOverlay}
+	files, err := g.generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	golden := filepath.Join("testdata", "golden")
+	if *update {
+		if err := os.RemoveAll(golden); err != nil {
+			t.Fatal(err)
+		}
+		for name, src := range files {
+			dest := filepath.Join(golden, filepath.FromSlash(name)+".golden")
+			if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(dest, src, 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	var want []string
+	err = filepath.WalkDir(golden, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(golden, path)
+		want = append(want, strings.TrimSuffix(filepath.ToSlash(rel), ".golden"))
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for name := range files {
+		got = append(got, name)
+	}
+	slices.Sort(want)
+	slices.Sort(got)
+	if !slices.Equal(got, want) {
+		t.Fatalf("generated files %v, want %v", got, want)
+	}
+	for _, name := range want {
+		expected, err := os.ReadFile(filepath.Join(golden, filepath.FromSlash(name)+".golden"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(files[name], expected) {
+			t.Errorf("%s differs from its golden file:\n%s", name, files[name])
+		}
+	}
+}
+
+// TestCheckedIn regenerates the module's files from openapi.yaml and fails
+// when the checked-in files differ, so that go generate has been run after
+// every change to the document or the generator.
+func TestCheckedIn(t *testing.T) {
+	root := filepath.Join("..", "..")
+	spec, err := openapi.Load(filepath.Join(root, "..", "openapi.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := &generator{spec: spec, source: "openapi.yaml", overlay: connectOverlay}
+	files, err := g.generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, src := range files {
+		current, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(name)))
+		if err != nil {
+			t.Errorf("%s: %v; run go generate", name, err)
+			continue
+		}
+		if !bytes.Equal(current, src) {
+			t.Errorf("%s is out of date; run go generate", name)
+		}
+	}
+}
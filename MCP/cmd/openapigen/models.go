@@ -0,0 +1,200 @@
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"slices"
+
+	"github.com/1password-connect/mcp-server/openapi"
+)
+
+// modelProperty is a property of a component schema, after allOf has been
+// flattened.
+type modelProperty struct {
+	Name     string
+	Schema   map[string]any
+	Required bool
+}
+
+// generateModels writes a Go type for every component schema.
+func (g *generator) generateModels() ([]byte, error) {
+	var buf bytes.Buffer
+	g.header(&buf, "models")
+	for _, name := range g.spec.SchemaNames() {
+		schema, err := g.spec.Component(name)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(&buf, "\n// %s represents the %s schema from the OpenAPI specification\n", name, name)
+		if schema["type"] != "array" {
+			if err := g.writeStruct(&buf, name, schema); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		items, _ := schema["items"].(map[string]any)
+		elemName, named := g.overlay.Elements[name]
+		if !named {
+			elem, err := g.goType(items, "")
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+			fmt.Fprintf(&buf, "type %s []%s\n", name, elem)
+			continue
+		}
+		fmt.Fprintf(&buf, "type %s []%s\n", name, elemName)
+		fmt.Fprintf(&buf, "\n// %s is an element of %s\n", elemName, name)
+		if err := g.writeStruct(&buf, elemName, items); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// writeStruct writes the struct type name for an object schema.
+func (g *generator) writeStruct(buf *bytes.Buffer, name string, schema map[string]any) error {
+	properties, err := g.properties(schema)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	fmt.Fprintf(buf, "type %s struct {\n", name)
+	for _, p := range properties {
+		typ, err := g.goType(p.Schema, "")
+		if err != nil {
+			return fmt.Errorf("%s.%s: %w", name, p.Name, err)
+		}
+		tag := p.Name
+		if !p.Required {
+			tag += ",omitempty"
+			// omitempty never leaves out a struct, so an optional object
+			// is a pointer to be left out when nil.
+			if g.isStruct(p.Schema) {
+				typ = "*" + typ
+			}
+		}
+		fmt.Fprintf(buf, "\t%s %s `json:\"%s\"`", modelField(p.Name), typ, tag)
+		if description := g.description(p.Schema); description != "" {
+			fmt.Fprintf(buf, " // %s", comment(description))
+		}
+		buf.WriteString("\n")
+	}
+	buf.WriteString("}\n")
+	return nil
+}
+
+// isStruct reports whether schema refers to a component that becomes a
+// struct type.
+func (g *generator) isStruct(schema map[string]any) bool {
+	name, ok := openapi.RefName(schema)
+	if !ok {
+		return false
+	}
+	target, err := g.spec.Component(name)
+	return err == nil && target["type"] != "array"
+}
+
+// properties lists the properties of an object schema in name order,
+// merging in those of the schemas it extends with allOf.
+func (g *generator) properties(schema map[string]any) ([]modelProperty, error) {
+	var properties []modelProperty
+	var required []string
+	var collect func(schema map[string]any) error
+	collect = func(schema map[string]any) error {
+		if name, ok := openapi.RefName(schema); ok {
+			target, err := g.spec.Component(name)
+			if err != nil {
+				return err
+			}
+			return collect(target)
+		}
+		if parts, ok := schema["allOf"].([]any); ok {
+			for _, part := range parts {
+				part, _ := part.(map[string]any)
+				if err := collect(part); err != nil {
+					return err
+				}
+			}
+		}
+		props, _ := schema["properties"].(map[string]any)
+		for name, p := range props {
+			p, ok := p.(map[string]any)
+			if !ok {
+				return fmt.Errorf("property %s must be a schema", name)
+			}
+			properties = append(properties, modelProperty{Name: name, Schema: p})
+		}
+		required = append(required, asStrings(schema["required"])...)
+		return nil
+	}
+	if err := collect(schema); err != nil {
+		return nil, err
+	}
+	slices.SortStableFunc(properties, func(a, b modelProperty) int { return cmp.Compare(a.Name, b.Name) })
+	properties = slices.CompactFunc(properties, func(a, b modelProperty) bool { return a.Name == b.Name })
+	for i := range properties {
+		properties[i].Required = slices.Contains(required, properties[i].Name)
+	}
+	return properties, nil
+}
+
+// goType returns the Go type for a schema. Component schemas are referred to
+// by name from pkg, and inline objects become maps.
+func (g *generator) goType(schema map[string]any, pkg string) (string, error) {
+	if name, ok := openapi.RefName(schema); ok {
+		if pkg != "" {
+			return pkg + "." + name, nil
+		}
+		return name, nil
+	}
+	if _, ok := schema["allOf"]; ok {
+		return "map[string]interface{}", nil
+	}
+	switch schema["type"] {
+	case "string":
+		return "string", nil
+	case "integer":
+		return "int", nil
+	case "number":
+		return "float64", nil
+	case "boolean":
+		return "bool", nil
+	case "object":
+		return "map[string]interface{}", nil
+	case "array":
+		items, _ := schema["items"].(map[string]any)
+		elem, err := g.goType(items, pkg)
+		if err != nil {
+			return "", err
+		}
+		return "[]" + elem, nil
+	case nil:
+		return "interface{}", nil
+	}
+	return "", fmt.Errorf("unsupported schema type %v", schema["type"])
+}
+
+// description returns a schema's description, or that of the component it
+// refers to.
+func (g *generator) description(schema map[string]any) string {
+	if name, ok := openapi.RefName(schema); ok {
+		target, err := g.spec.Component(name)
+		if err != nil {
+			return ""
+		}
+		return g.description(target)
+	}
+	description, _ := schema["description"].(string)
+	return description
+}
+
+func asStrings(v any) []string {
+	list, _ := v.([]any)
+	var out []string
+	for _, entry := range list {
+		if s, ok := entry.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+)
+
+// modelField names the Go field of a schema property. The models have
+// always capitalised only the first letter, so createdAt is Createdat, and
+// type becomes TypeField.
+func modelField(name string) string {
+	if name == "type" {
+		return "TypeField"
+	}
+	return upperFirst(strings.ToLower(name))
+}
+
+// paramField names the Go field of an operation parameter: vaultUuid is
+// VaultUuid and inline_files is InlineFiles.
+func paramField(name string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(name, "_") {
+		b.WriteString(upperFirst(part))
+	}
+	return b.String()
+}
+
+// toolName names the tool for an operation after its method and path, so
+// GET /vaults/{vaultUuid} is get_vaults_vaultUuid.
+func toolName(method, path string) string {
+	parts := []string{strings.ToLower(method)}
+	for _, segment := range strings.Split(path, "/") {
+		segment = strings.Trim(segment, "{}")
+		if segment != "" {
+			parts = append(parts, segment)
+		}
+	}
+	return strings.Join(parts, "_")
+}
+
+// createFunc names the exported function that builds an operation's tool,
+// such as CreateGetvaultsTool for GetVaults.
+func createFunc(opID string) string {
+	return "Create" + upperFirst(strings.ToLower(opID)) + "Tool"
+}
+
+func upperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	r := []rune(s)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	r := []rune(s)
+	r[0] = unicode.ToLower(r[0])
+	return string(r)
+}
+
+// comment flattens a description onto one line for a Go comment.
+func comment(description string) string {
+	return strings.Join(strings.Fields(description), " ")
+}
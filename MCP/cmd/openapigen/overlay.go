@@ -0,0 +1,60 @@
+package main
+
+// overlay holds what the generated code says differently from the OpenAPI
+// document, keyed by operation ID.
+type overlay struct {
+	// Module is the import path of the module the code is generated into.
+	Module string
+	// Titles are the human titles of tools. Tools without one use the
+	// operation's summary.
+	Titles map[string]string
+	// Descriptions replace the summary and description of operations.
+	Descriptions map[string]string
+	// Resolvable path parameters also accept names, which the handlers
+	// resolve to UUIDs, so their descriptions say so.
+	Resolvable []string
+	// Filled lists required body properties the handler supplies itself,
+	// so that they are optional arguments.
+	Filled map[string][]string
+	// Elements name the types generated for the inline object items of
+	// array component schemas, keyed by schema name. Items of other arrays
+	// become maps.
+	Elements map[string]string
+}
+
+var connectOverlay = overlay{
+	Module: "github.com/1password-connect/mcp-server",
+	Titles: map[string]string{
+		"GetApiActivity":       "List API activity",
+		"GetServerHealth":      "Get server health",
+		"GetHeartbeat":         "Check heartbeat",
+		"GetPrometheusMetrics": "Get Prometheus metrics",
+		"GetVaults":            "List vaults",
+		"GetVaultById":         "Get vault",
+		"GetVaultItems":        "List items",
+		"CreateVaultItem":      "Create item",
+		"DeleteVaultItem":      "Delete item",
+		"GetVaultItemById":     "Get item",
+		"PatchVaultItem":       "Patch item",
+		"UpdateVaultItem":      "Replace item",
+		"GetItemFiles":         "List item files",
+		"GetDetailsOfFileById": "Get file details",
+		"DownloadFileByID":     "Download file",
+	},
+	Descriptions: map[string]string{
+		"CreateVaultItem":  "Create a new Item. `vault` may be left out; it defaults to the Vault given by vaultUuid.",
+		"UpdateVaultItem":  "Update an Item, replacing it as a whole. `vault` may be left out; it defaults to the Vault given by vaultUuid.",
+		"DownloadFileByID": "Get the content of a File. Text files are returned as text, binary files as an embedded base64 resource.",
+		"PatchVaultItem": "Update a subset of Item attributes with an [RFC6902 JSON Patch](https://tools.ietf.org/html/rfc6902) document. Only `add`, `remove` and `replace` operations are supported, on these paths:\n\n" +
+			"- `/title`\n- `/tags`, where adding a single tag appends it\n- `/sections`\n- `/fields`, to add a field\n" +
+			"- `/fields/{fieldId}`, to replace or remove a field\n- `/fields/{fieldId}/value`",
+	},
+	Resolvable: []string{"vaultUuid", "itemUuid"},
+	Filled: map[string][]string{
+		"CreateVaultItem": {"vault"},
+		"UpdateVaultItem": {"vault"},
+	},
+	Elements: map[string]string{
+		"Patch": "PatchOperation",
+	},
+}
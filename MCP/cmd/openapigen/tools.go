@@ -0,0 +1,263 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/1password-connect/mcp-server/openapi"
+)
+
+// bodyArg is the argument that holds request bodies that are not objects.
+const bodyArg = "items"
+
+// generateOperation writes the parameters, parser and definition of the
+// tool for op. The handler and the exported constructor stay hand-written,
+// since they differ in how they call Connect and present the result.
+func (g *generator) generateOperation(op openapi.Operation) ([]byte, error) {
+	ident := lowerFirst(op.ID)
+	name := toolName(op.Method, op.Path)
+
+	var query []openapi.Parameter
+	for _, p := range op.Parameters {
+		switch {
+		case p.In == "path" && p.Schema["type"] == "string":
+		case p.In == "query" && !p.Required:
+			query = append(query, p)
+		default:
+			return nil, fmt.Errorf("unsupported %s parameter %s", p.In, p.Name)
+		}
+	}
+	body, err := g.body(op)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	g.header(&buf, "tools")
+	buf.WriteString("import (\n")
+	buf.WriteString("\t\"net/http\"\n")
+	if len(query) > 0 {
+		buf.WriteString("\t\"net/url\"\n")
+		if slices.ContainsFunc(query, func(p openapi.Parameter) bool { return p.Schema["type"] != "string" }) {
+			buf.WriteString("\t\"strconv\"\n")
+		}
+	}
+	buf.WriteString("\n")
+	if body != nil {
+		fmt.Fprintf(&buf, "\t%q\n", g.overlay.Module+"/models")
+	}
+	fmt.Fprintf(&buf, "\t%q\n", g.overlay.Module+"/tools/common")
+	buf.WriteString("\t\"github.com/mark3labs/mcp-go/mcp\"\n)\n")
+
+	if len(op.Parameters) == 0 && body == nil {
+		g.writeDefinition(&buf, op, nil)
+		return buf.Bytes(), nil
+	}
+
+	// Parameters
+	fmt.Fprintf(&buf, "\n// %sParams holds the arguments of the %s tool.\n", ident, name)
+	fmt.Fprintf(&buf, "type %sParams struct {\n", ident)
+	for _, p := range op.Parameters {
+		typ := "string"
+		if p.In == "query" {
+			typ = map[any]string{"string": "*string", "integer": "*int", "boolean": "*bool"}[p.Schema["type"]]
+			if typ == "" {
+				return nil, fmt.Errorf("unsupported query parameter type %v", p.Schema["type"])
+			}
+		}
+		fmt.Fprintf(&buf, "\t%s %s", paramField(p.Name), typ)
+		if description := g.paramDescription(p); description != "" {
+			fmt.Fprintf(&buf, " // %s", comment(description))
+		}
+		buf.WriteString("\n")
+	}
+	if body != nil {
+		fmt.Fprintf(&buf, "\tBody models.%s\n", body.Name)
+	}
+	buf.WriteString("}\n")
+
+	// Parser
+	fmt.Fprintf(&buf, "\n// parse%sParams reads the arguments of the %s tool.\n", upperFirst(ident), name)
+	fmt.Fprintf(&buf, "func parse%sParams(args map[string]any) (%sParams, error) {\n", upperFirst(ident), ident)
+	fmt.Fprintf(&buf, "\tvar p %sParams\n", ident)
+	if len(op.Parameters) > 0 {
+		buf.WriteString("\tvar err error\n")
+	}
+	for _, p := range op.Parameters {
+		read := "PathParam"
+		if p.In == "query" {
+			read = map[any]string{"string": "QueryString", "integer": "QueryInt", "boolean": "QueryBool"}[p.Schema["type"]]
+		}
+		fmt.Fprintf(&buf, "\tif p.%s, err = common.%s(args, %q); err != nil {\n\t\treturn p, err\n\t}\n", paramField(p.Name), read, p.Name)
+	}
+	if body != nil {
+		if body.Object {
+			buf.WriteString("\tif err := common.DecodeBody(args, &p.Body); err != nil {\n\t\treturn p, err\n\t}\n")
+		} else {
+			fmt.Fprintf(&buf, "\tif err := common.DecodeBodyArg(args, %q, &p.Body); err != nil {\n\t\treturn p, err\n\t}\n", bodyArg)
+		}
+	}
+	buf.WriteString("\treturn p, nil\n}\n")
+
+	// Query
+	if len(query) > 0 {
+		buf.WriteString("\n// query returns the query parameters that are set.\n")
+		fmt.Fprintf(&buf, "func (p %sParams) query() url.Values {\n\tquery := url.Values{}\n", ident)
+		for _, q := range query {
+			field := "p." + paramField(q.Name)
+			value := map[any]string{
+				"string":  "*" + field,
+				"integer": "strconv.Itoa(*" + field + ")",
+				"boolean": "strconv.FormatBool(*" + field + ")",
+			}[q.Schema["type"]]
+			fmt.Fprintf(&buf, "\tif %s != nil {\n\t\tquery.Set(%q, %s)\n\t}\n", field, q.Name, value)
+		}
+		buf.WriteString("\treturn query\n}\n")
+	}
+
+	g.writeDefinition(&buf, op, body)
+	return buf.Bytes(), nil
+}
+
+// writeDefinition writes the function returning the tool definition of op.
+func (g *generator) writeDefinition(buf *bytes.Buffer, op openapi.Operation, body *requestBody) {
+	ident := lowerFirst(op.ID)
+	name := toolName(op.Method, op.Path)
+	title := g.overlay.Titles[op.ID]
+	if title == "" {
+		title = op.Summary
+	}
+	fmt.Fprintf(buf, "\n// %sTool returns the definition of the %s tool, followed by opts.\n", ident, name)
+	fmt.Fprintf(buf, "func %sTool(opts ...mcp.ToolOption) mcp.Tool {\n", ident)
+	fmt.Fprintf(buf, "\treturn common.NewTool(%q, %q, http.Method%s, append([]mcp.ToolOption{\n",
+		name, title, upperFirst(strings.ToLower(op.Method)))
+	fmt.Fprintf(buf, "\t\tmcp.WithDescription(%q),\n", g.opDescription(op))
+	for _, p := range op.Parameters {
+		buf.WriteString("\t\t" + g.paramOption(p) + ",\n")
+	}
+	if body != nil {
+		if body.Object {
+			fmt.Fprintf(buf, "\t\tcommon.WithBody(%s", body.Const)
+			for _, filled := range g.overlay.Filled[op.ID] {
+				fmt.Fprintf(buf, ", %q", filled)
+			}
+			buf.WriteString("),\n")
+		} else {
+			fmt.Fprintf(buf, "\t\tcommon.WithBodyArg(%q, %s),\n", bodyArg, body.Const)
+		}
+	}
+	buf.WriteString("\t}, opts...)...)\n}\n")
+}
+
+// requestBody describes the body schema of an operation.
+type requestBody struct {
+	Name   string // Component schema
+	Const  string // Constant holding its JSON schema
+	Object bool   // Whether the arguments themselves are the body
+}
+
+func (g *generator) body(op openapi.Operation) (*requestBody, error) {
+	if op.Body == nil {
+		return nil, nil
+	}
+	name, ok := openapi.RefName(op.Body)
+	if !ok {
+		return nil, fmt.Errorf("request bodies must refer to a component schema")
+	}
+	schema, err := g.spec.Component(name)
+	if err != nil {
+		return nil, err
+	}
+	return &requestBody{
+		Name:   name,
+		Const:  lowerFirst(name) + "RequestSchema",
+		Object: schema["type"] != "array",
+	}, nil
+}
+
+// generateSchemas writes the request body schemas the operations in ops
+// use as JSON constants.
+func (g *generator) generateSchemas(ops []openapi.Operation) ([]byte, error) {
+	var bodies []*requestBody
+	for _, op := range ops {
+		body, err := g.body(op)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op.ID, err)
+		}
+		if body != nil && !slices.ContainsFunc(bodies, func(b *requestBody) bool { return b.Name == body.Name }) {
+			bodies = append(bodies, body)
+		}
+	}
+	if len(bodies) == 0 {
+		return nil, nil
+	}
+	slices.SortFunc(bodies, func(a, b *requestBody) int { return strings.Compare(a.Name, b.Name) })
+
+	var buf bytes.Buffer
+	g.header(&buf, "tools")
+	for _, body := range bodies {
+		schema, err := g.spec.Schema(body.Name, openapi.Request)
+		if err != nil {
+			return nil, err
+		}
+		data, err := json.MarshalIndent(schema, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(&buf, "\n// %s is the %s schema as accepted in request bodies.\n", body.Const, body.Name)
+		fmt.Fprintf(&buf, "const %s = %s\n", body.Const, rawString(string(data)))
+	}
+	return buf.Bytes(), nil
+}
+
+func (g *generator) opDescription(op openapi.Operation) string {
+	if description, ok := g.overlay.Descriptions[op.ID]; ok {
+		return description
+	}
+	if op.Description == "" {
+		return op.Summary
+	}
+	return op.Summary + "\n\n" + strings.TrimSpace(op.Description)
+}
+
+func (g *generator) paramDescription(p openapi.Parameter) string {
+	if p.In == "path" && slices.Contains(g.overlay.Resolvable, p.Name) {
+		return strings.Replace(p.Description, "The UUID of", "The UUID or name of", 1)
+	}
+	return p.Description
+}
+
+// paramOption returns the mcp option declaring p as an argument.
+func (g *generator) paramOption(p openapi.Parameter) string {
+	with := map[any]string{"string": "WithString", "integer": "WithInteger", "boolean": "WithBoolean"}[p.Schema["type"]]
+	opts := []string{strconv.Quote(p.Name)}
+	if p.Required {
+		opts = append(opts, "mcp.Required()")
+	}
+	if description := g.paramDescription(p); description != "" {
+		opts = append(opts, fmt.Sprintf("mcp.Description(%q)", description))
+	}
+	if pattern, ok := p.Schema["pattern"].(string); ok && !slices.Contains(g.overlay.Resolvable, p.Name) {
+		opts = append(opts, fmt.Sprintf("mcp.Pattern(%q)", pattern))
+	}
+	if values := asStrings(p.Schema["enum"]); len(values) > 0 {
+		quoted := make([]string, len(values))
+		for i, v := range values {
+			quoted[i] = strconv.Quote(v)
+		}
+		opts = append(opts, "mcp.Enum("+strings.Join(quoted, ", ")+")")
+	}
+	switch def := p.Schema["default"].(type) {
+	case int:
+		opts = append(opts, fmt.Sprintf("mcp.DefaultNumber(%d)", def))
+	case bool:
+		opts = append(opts, fmt.Sprintf("mcp.DefaultBool(%t)", def))
+	case string:
+		opts = append(opts, fmt.Sprintf("mcp.DefaultString(%q)", def))
+	}
+	return fmt.Sprintf("mcp.%s(%s)", with, strings.Join(opts, ", "))
+}
@@ -1,16 +1,113 @@
 package config
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/1password-connect/mcp-server/policy"
 )
 
+// DefaultMaxDownloadSize is the largest file body, in bytes, the download tool
+// will buffer when MAX_DOWNLOAD_SIZE is not set.
+const DefaultMaxDownloadSize int64 = 10 << 20
+
+// DefaultRequestTimeout bounds a single Connect API call when REQUEST_TIMEOUT
+// is not set.
+const DefaultRequestTimeout = 30 * time.Second
+
+// DefaultSessionTTL is how long an idle HTTP session is kept when SESSION_TTL
+// is not set.
+const DefaultSessionTTL = 30 * time.Minute
+
+// DefaultPollInterval is how often subscribed vaults are checked for changes
+// when POLL_INTERVAL is not set.
+const DefaultPollInterval = 30 * time.Second
+
+// DefaultPollMaxBackoff bounds the wait between polls of a vault that keeps
+// failing when POLL_MAX_BACKOFF is not set.
+const DefaultPollMaxBackoff = 5 * time.Minute
+
+// RetryPolicy controls how transient Connect failures are retried.
+type RetryPolicy struct {
+	MaxAttempts int           // Total attempts per request, including the first; 1 disables retries
+	BaseDelay   time.Duration // Wait before the first retry; doubled for each further retry
+	MaxDelay    time.Duration // Upper bound for the backoff between attempts
+	Jitter      float64       // Fraction, between 0 and 1, of each delay that is randomised
+}
+
+// DefaultRetryPolicy applies when the RETRY_* variables are not set.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   250 * time.Millisecond,
+	MaxDelay:    5 * time.Second,
+	Jitter:      0.2,
+}
+
+// Supported values for APIConfig.RedactionMode.
+const (
+	RedactAlways    = "always"    // Mask secrets in every response
+	RedactConcealed = "concealed" // Mask only CONCEALED field values
+	RedactReveal    = "reveal"    // Mask secrets unless a tool call passes reveal: true
+)
+
+// Supported values for APIConfig.AuthType.
+const (
+	AuthBearer = "bearer"
+	AuthAPIKey = "api_key"
+	AuthBasic  = "basic"
+	AuthNone   = "none"
+)
+
+// DefaultAPIKeyHeader carries API_KEY when API_KEY_HEADER is not set.
+const DefaultAPIKeyHeader = "X-API-Key"
+
 type APIConfig struct {
-	BaseURL     string
-	BearerToken string // For OAuth2/Bearer authentication
-	APIKey      string // For API key authentication
-	BasicAuth   string // For basic authentication
-	Port        string // For server port configuration
+	BaseURL      string
+	BearerToken  string // For OAuth2/Bearer authentication
+	APIKey       string // For API key authentication
+	APIKeyHeader string // Header that carries APIKey
+	BasicAuth    string // For basic authentication, as "user:password" or its base64 encoding
+	AuthType     string // Explicit scheme; inferred from the credentials when empty
+	Port         string // For server port configuration
+
+	MaxDownloadSize    int64          // Upper bound in bytes for downloaded file contents
+	RequestTimeout     time.Duration  // Timeout for a single Connect API call
+	SessionTTL         time.Duration  // Idle lifetime of an HTTP session
+	Retry              RetryPolicy    // Retries for transient Connect failures
+	PollInterval       time.Duration  // Interval between checks of subscribed vaults
+	PollMaxBackoff     time.Duration  // Upper bound for the poll interval after failures
+	RedactionMode      string         // Which secret values are masked in tool outputs
+	ReadOnly           bool           // Refuse every request that could modify a vault
+	ConfirmDestructive bool           // Ask the user before deleting, replacing or removing data
+	Policy             *policy.Policy // Tools and vaults the server may use; nil allows all
+}
+
+// IsNetworkTransport reports whether a TRANSPORT value selects one of the
+// HTTP-based transports, in which API configuration arrives with each client.
+func IsNetworkTransport(transport string) bool {
+	switch transport {
+	case "http", "HTTP", "https", "HTTPS", "sse", "SSE":
+		return true
+	}
+	return false
+}
+
+type contextKey struct{}
+
+// WithContext returns a copy of ctx carrying cfg, for handlers serving a
+// session whose credentials differ from the server's own configuration.
+func WithContext(ctx context.Context, cfg *APIConfig) context.Context {
+	return context.WithValue(ctx, contextKey{}, cfg)
+}
+
+// FromContext returns the APIConfig stored in ctx by WithContext.
+func FromContext(ctx context.Context) (*APIConfig, bool) {
+	cfg, ok := ctx.Value(contextKey{}).(*APIConfig)
+	return cfg, ok
 }
 
 func LoadAPIConfig() (*APIConfig, error) {
@@ -28,21 +125,207 @@ func LoadAPIConfig() (*APIConfig, error) {
 		transport = os.Getenv("transport")
 	}
 	
-	// For STDIO mode (transport is not "http"/"HTTP"/"https"/"HTTPS"), API_BASE_URL is required from environment
-	if transport != "http" && transport != "HTTP" && transport != "https" && transport != "HTTPS" && baseURL == "" {
+	// For STDIO mode (transport is not "http"/"https"/"sse"), API_BASE_URL is required from environment
+	if !IsNetworkTransport(transport) && baseURL == "" {
 		return nil, fmt.Errorf("API_BASE_URL environment variable not set")
 	}
 	
-	// For HTTP/HTTPS mode (transport is "http"/"HTTP"/"https"/"HTTPS"), API_BASE_URL comes from headers
+	// For HTTP/HTTPS/SSE mode (transport is "http"/"https"/"sse"), API_BASE_URL comes from headers
 	// so we don't require it from environment variables
 
+	maxDownloadSize := DefaultMaxDownloadSize
+	if v := os.Getenv("MAX_DOWNLOAD_SIZE"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid MAX_DOWNLOAD_SIZE %q: must be a positive number of bytes", v)
+		}
+		maxDownloadSize = n
+	}
+
+	requestTimeout := DefaultRequestTimeout
+	if v := os.Getenv("REQUEST_TIMEOUT"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid REQUEST_TIMEOUT %q: must be a positive duration such as 30s", v)
+		}
+		requestTimeout = d
+	}
+
+	sessionTTL := DefaultSessionTTL
+	if v := os.Getenv("SESSION_TTL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid SESSION_TTL %q: must be a positive duration such as 30m", v)
+		}
+		sessionTTL = d
+	}
+
+	pollInterval := DefaultPollInterval
+	if v := os.Getenv("POLL_INTERVAL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid POLL_INTERVAL %q: must be a positive duration such as 30s", v)
+		}
+		pollInterval = d
+	}
+
+	pollMaxBackoff := DefaultPollMaxBackoff
+	if v := os.Getenv("POLL_MAX_BACKOFF"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < pollInterval {
+			return nil, fmt.Errorf("invalid POLL_MAX_BACKOFF %q: must be a duration no shorter than POLL_INTERVAL", v)
+		}
+		pollMaxBackoff = d
+	}
+
+	redactionMode := strings.ToLower(os.Getenv("REDACTION_MODE"))
+	switch redactionMode {
+	case "":
+		redactionMode = RedactReveal
+	case RedactAlways, RedactConcealed, RedactReveal:
+	default:
+		return nil, fmt.Errorf("invalid REDACTION_MODE %q: must be one of always, concealed or reveal", redactionMode)
+	}
+
+	readOnly := false
+	if v := os.Getenv("READ_ONLY"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid READ_ONLY %q: must be true or false", v)
+		}
+		readOnly = b
+	}
+
+	confirmDestructive := false
+	if v := os.Getenv("CONFIRM_DESTRUCTIVE"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CONFIRM_DESTRUCTIVE %q: must be true or false", v)
+		}
+		confirmDestructive = b
+	}
+
+	var toolPolicy *policy.Policy
+	if v := os.Getenv("POLICY_FILE"); v != "" {
+		p, err := policy.Load(v)
+		if err != nil {
+			return nil, err
+		}
+		toolPolicy = p
+	}
+
+	retry, err := loadRetryPolicy()
+	if err != nil {
+		return nil, err
+	}
+
+	apiKeyHeader := os.Getenv("API_KEY_HEADER")
+	if apiKeyHeader == "" {
+		apiKeyHeader = DefaultAPIKeyHeader
+	}
+
 	return &APIConfig{
-		BaseURL:     baseURL,
-		BearerToken: os.Getenv("BEARER_TOKEN"),
-		APIKey:      os.Getenv("API_KEY"),
-		BasicAuth:   os.Getenv("BASIC_AUTH"),
-		Port:        port,
+		BaseURL:            baseURL,
+		BearerToken:        os.Getenv("BEARER_TOKEN"),
+		APIKey:             os.Getenv("API_KEY"),
+		APIKeyHeader:       apiKeyHeader,
+		BasicAuth:          os.Getenv("BASIC_AUTH"),
+		AuthType:           strings.ToLower(os.Getenv("AUTH_TYPE")),
+		Port:               port,
+		MaxDownloadSize:    maxDownloadSize,
+		RequestTimeout:     requestTimeout,
+		SessionTTL:         sessionTTL,
+		Retry:              retry,
+		PollInterval:       pollInterval,
+		PollMaxBackoff:     max(pollMaxBackoff, pollInterval),
+		RedactionMode:      redactionMode,
+		ReadOnly:           readOnly,
+		ConfirmDestructive: confirmDestructive,
+		Policy:             toolPolicy,
 	}, nil
 }
 
+func loadRetryPolicy() (RetryPolicy, error) {
+	policy := DefaultRetryPolicy
+	if v := os.Getenv("RETRY_MAX_ATTEMPTS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return policy, fmt.Errorf("invalid RETRY_MAX_ATTEMPTS %q: must be a positive number", v)
+		}
+		policy.MaxAttempts = n
+	}
+	if v := os.Getenv("RETRY_BASE_DELAY"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return policy, fmt.Errorf("invalid RETRY_BASE_DELAY %q: must be a positive duration such as 250ms", v)
+		}
+		policy.BaseDelay = d
+	}
+	if v := os.Getenv("RETRY_MAX_DELAY"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return policy, fmt.Errorf("invalid RETRY_MAX_DELAY %q: must be a positive duration such as 5s", v)
+		}
+		policy.MaxDelay = d
+	}
+	if v := os.Getenv("RETRY_JITTER"); v != "" {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil || f < 0 || f > 1 {
+			return policy, fmt.Errorf("invalid RETRY_JITTER %q: must be a fraction between 0 and 1", v)
+		}
+		policy.Jitter = f
+	}
+	return policy, nil
+}
+
+// ResolveAuthType returns the authentication scheme the configuration selects
+// and fails unless exactly one scheme is configured. When AuthType is empty
+// the scheme is inferred from whichever single credential is set; AuthNone
+// must always be requested explicitly.
+func (c *APIConfig) ResolveAuthType() (string, error) {
+	credentials := map[string]string{
+		AuthBearer: c.BearerToken,
+		AuthAPIKey: c.APIKey,
+		AuthBasic:  c.BasicAuth,
+	}
+	envNames := map[string]string{
+		AuthBearer: "BEARER_TOKEN",
+		AuthAPIKey: "API_KEY",
+		AuthBasic:  "BASIC_AUTH",
+	}
+	var configured []string
+	for _, scheme := range []string{AuthBearer, AuthAPIKey, AuthBasic} {
+		if credentials[scheme] != "" {
+			configured = append(configured, scheme)
+		}
+	}
 
+	switch c.AuthType {
+	case "":
+		switch len(configured) {
+		case 0:
+			return "", fmt.Errorf("no credentials configured: set one of BEARER_TOKEN, API_KEY or BASIC_AUTH, or AUTH_TYPE=none")
+		case 1:
+			return configured[0], nil
+		}
+	case AuthNone:
+		if len(configured) == 0 {
+			return AuthNone, nil
+		}
+	case AuthBearer, AuthAPIKey, AuthBasic:
+		if credentials[c.AuthType] == "" {
+			return "", fmt.Errorf("AUTH_TYPE=%s requires %s to be set", c.AuthType, envNames[c.AuthType])
+		}
+		if len(configured) == 1 {
+			return c.AuthType, nil
+		}
+	default:
+		return "", fmt.Errorf("invalid AUTH_TYPE %q: must be one of bearer, api_key, basic or none", c.AuthType)
+	}
+
+	names := make([]string, len(configured))
+	for i, scheme := range configured {
+		names[i] = envNames[scheme]
+	}
+	return "", fmt.Errorf("multiple credentials configured (%s): exactly one authentication scheme is allowed", strings.Join(names, ", "))
+}
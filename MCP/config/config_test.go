@@ -0,0 +1,43 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestResolveAuthType(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  APIConfig
+		want string // The scheme, or a substring of the error when wantErr
+		err  bool
+	}{
+		{name: "bearer inferred", cfg: APIConfig{BearerToken: "t"}, want: AuthBearer},
+		{name: "api key inferred", cfg: APIConfig{APIKey: "k"}, want: AuthAPIKey},
+		{name: "basic inferred", cfg: APIConfig{BasicAuth: "u:p"}, want: AuthBasic},
+		{name: "explicit bearer", cfg: APIConfig{AuthType: AuthBearer, BearerToken: "t"}, want: AuthBearer},
+		{name: "explicit none", cfg: APIConfig{AuthType: AuthNone}, want: AuthNone},
+
+		{name: "nothing configured", cfg: APIConfig{}, err: true, want: "no credentials configured"},
+		{name: "two credentials", cfg: APIConfig{BearerToken: "t", APIKey: "k"}, err: true, want: "multiple credentials configured (BEARER_TOKEN, API_KEY)"},
+		{name: "explicit type with conflicting credentials", cfg: APIConfig{AuthType: AuthBasic, BasicAuth: "u:p", BearerToken: "t"}, err: true, want: "multiple credentials configured"},
+		{name: "explicit type without its credential", cfg: APIConfig{AuthType: AuthAPIKey, BearerToken: "t"}, err: true, want: "AUTH_TYPE=api_key requires API_KEY"},
+		{name: "none with credentials", cfg: APIConfig{AuthType: AuthNone, BasicAuth: "u:p"}, err: true, want: "multiple credentials configured (BASIC_AUTH)"},
+		{name: "unknown type", cfg: APIConfig{AuthType: "digest", BearerToken: "t"}, err: true, want: `invalid AUTH_TYPE "digest"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.cfg.ResolveAuthType()
+			switch {
+			case tt.err && err == nil:
+				t.Errorf("got scheme %q, want an error about %q", got, tt.want)
+			case tt.err && !strings.Contains(err.Error(), tt.want):
+				t.Errorf("error = %v, want it to mention %q", err, tt.want)
+			case !tt.err && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case !tt.err && got != tt.want:
+				t.Errorf("scheme = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
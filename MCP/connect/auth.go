@@ -0,0 +1,90 @@
+package connect
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/1password-connect/mcp-server/config"
+)
+
+// Authenticator applies credentials to an outgoing Connect request.
+type Authenticator interface {
+	Authenticate(req *http.Request)
+}
+
+// BearerAuth sends the token in an "Authorization: Bearer" header.
+type BearerAuth struct {
+	Token string
+}
+
+func (a BearerAuth) Authenticate(req *http.Request) {
+	req.Header.Set("Authorization", "Bearer "+a.Token)
+}
+
+// APIKeyAuth sends the key verbatim in a configurable header.
+type APIKeyAuth struct {
+	Header string
+	Key    string
+}
+
+func (a APIKeyAuth) Authenticate(req *http.Request) {
+	req.Header.Set(a.Header, a.Key)
+}
+
+// BasicAuth sends HTTP basic credentials.
+type BasicAuth struct {
+	Username string
+	Password string
+}
+
+func (a BasicAuth) Authenticate(req *http.Request) {
+	req.SetBasicAuth(a.Username, a.Password)
+}
+
+// NoAuth leaves requests unauthenticated.
+type NoAuth struct{}
+
+func (NoAuth) Authenticate(*http.Request) {}
+
+// NewAuthenticator returns the Authenticator selected by cfg. It fails unless
+// exactly one authentication scheme is configured.
+func NewAuthenticator(cfg *config.APIConfig) (Authenticator, error) {
+	scheme, err := cfg.ResolveAuthType()
+	if err != nil {
+		return nil, err
+	}
+	switch scheme {
+	case config.AuthBearer:
+		return BearerAuth{Token: cfg.BearerToken}, nil
+	case config.AuthAPIKey:
+		header := cfg.APIKeyHeader
+		if header == "" {
+			header = config.DefaultAPIKeyHeader
+		}
+		return APIKeyAuth{Header: header, Key: cfg.APIKey}, nil
+	case config.AuthBasic:
+		username, password, err := parseBasicAuth(cfg.BasicAuth)
+		if err != nil {
+			return nil, err
+		}
+		return BasicAuth{Username: username, Password: password}, nil
+	default:
+		return NoAuth{}, nil
+	}
+}
+
+// parseBasicAuth accepts "user:password" or its base64 encoding.
+func parseBasicAuth(value string) (string, string, error) {
+	if username, password, ok := strings.Cut(value, ":"); ok {
+		return username, password, nil
+	}
+	decoded, err := base64.StdEncoding.DecodeString(value)
+	if err == nil {
+		if username, password, ok := strings.Cut(string(decoded), ":"); ok {
+			return username, password, nil
+		}
+	}
+	return "", "", fmt.Errorf("invalid BASIC_AUTH: expected user:password or its base64 encoding")
+}
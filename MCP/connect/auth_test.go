@@ -0,0 +1,75 @@
+package connect
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/1password-connect/mcp-server/config"
+	"github.com/1password-connect/mcp-server/connect/connecttest"
+)
+
+func TestNewAuthenticator(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.APIConfig
+		want Authenticator // nil when an error is expected
+	}{
+		{name: "bearer", cfg: config.APIConfig{BearerToken: "t"}, want: BearerAuth{Token: "t"}},
+		{name: "api key in the default header", cfg: config.APIConfig{APIKey: "k"}, want: APIKeyAuth{Header: config.DefaultAPIKeyHeader, Key: "k"}},
+		{name: "api key in a custom header", cfg: config.APIConfig{APIKey: "k", APIKeyHeader: "X-Custom-Key"}, want: APIKeyAuth{Header: "X-Custom-Key", Key: "k"}},
+		{name: "basic as user:pass", cfg: config.APIConfig{BasicAuth: "alice:s3cret:x"}, want: BasicAuth{Username: "alice", Password: "s3cret:x"}},
+		{name: "basic as base64", cfg: config.APIConfig{BasicAuth: "YWxpY2U6czNjcmV0"}, want: BasicAuth{Username: "alice", Password: "s3cret"}},
+		{name: "none", cfg: config.APIConfig{AuthType: config.AuthNone}, want: NoAuth{}},
+
+		{name: "basic without a colon", cfg: config.APIConfig{BasicAuth: "alice"}},
+		{name: "base64 without a colon", cfg: config.APIConfig{BasicAuth: "YWxpY2U="}},
+		{name: "conflicting credentials", cfg: config.APIConfig{AuthType: config.AuthBearer, BearerToken: "t", APIKey: "k"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewAuthenticator(&tt.cfg)
+			switch {
+			case tt.want == nil && err == nil:
+				t.Errorf("got %#v, want an error", got)
+			case tt.want != nil && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case !reflect.DeepEqual(got, tt.want) && tt.want != nil:
+				t.Errorf("authenticator = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestAuthHeaders checks the headers that reach a Connect server for each
+// scheme.
+func TestAuthHeaders(t *testing.T) {
+	tests := []struct {
+		name   string
+		cfg    config.APIConfig
+		header string
+		want   string
+	}{
+		{name: "bearer", cfg: config.APIConfig{BearerToken: "t"}, header: "Authorization", want: "Bearer t"},
+		{name: "api key", cfg: config.APIConfig{APIKey: "k", APIKeyHeader: "X-Custom-Key"}, header: "X-Custom-Key", want: "k"},
+		{name: "basic", cfg: config.APIConfig{BasicAuth: "alice:s3cret"}, header: "Authorization", want: "Basic YWxpY2U6czNjcmV0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := connecttest.NewServer()
+			defer fake.Close()
+			cfg := tt.cfg
+			cfg.BaseURL = fake.APIConfig().BaseURL
+			if _, err := NewClient(&cfg).Get(context.Background(), "/health", nil, nil); err != nil {
+				t.Fatal(err)
+			}
+			requests := fake.Requests()
+			if len(requests) != 1 {
+				t.Fatalf("got %d requests, want 1", len(requests))
+			}
+			if got := requests[0].Header.Get(tt.header); got != tt.want {
+				t.Errorf("%s = %q, want %q", tt.header, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,280 @@
+// Package connect is the HTTP client every MCP tool uses to talk to a
+// 1Password Connect server.
+package connect
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/1password-connect/mcp-server/config"
+	"github.com/1password-connect/mcp-server/policy"
+)
+
+// UserAgent is sent with every request made to the Connect server.
+const UserAgent = "1password-connect-mcp-server/1.5.7"
+
+// maxResponseSize bounds how much of a response body Do will buffer.
+const maxResponseSize = 32 << 20
+
+// maxErrorBodySize bounds how much of a streamed error response is read.
+const maxErrorBodySize = 64 << 10
+
+// Client performs requests against the Connect API described by an APIConfig.
+type Client struct {
+	baseURL     string
+	timeout     time.Duration
+	retryPolicy config.RetryPolicy
+	readOnly    bool
+	policy      *policy.Policy
+	auth        Authenticator
+	authErr     error
+	httpClient  *http.Client
+}
+
+// NewClient returns a Client for cfg. Clients share a pooled transport, so
+// creating one per configuration is cheap. Credentials are validated at
+// startup; if cfg is nonetheless invalid every request fails with the
+// validation error rather than being sent unauthenticated.
+func NewClient(cfg *config.APIConfig) *Client {
+	timeout := cfg.RequestTimeout
+	if timeout <= 0 {
+		timeout = config.DefaultRequestTimeout
+	}
+	auth, err := NewAuthenticator(cfg)
+	return &Client{
+		baseURL:     strings.TrimRight(cfg.BaseURL, "/"),
+		timeout:     timeout,
+		retryPolicy: retryPolicy(cfg.Retry),
+		readOnly:    cfg.ReadOnly,
+		policy:      cfg.Policy,
+		auth:        auth,
+		authErr:     err,
+		httpClient:  &http.Client{Transport: sharedTransport},
+	}
+}
+
+// ClientFor returns a client for the APIConfig bound to ctx with
+// config.WithContext, or fallback when ctx carries none.
+func ClientFor(ctx context.Context, fallback *Client) *Client {
+	if cfg, ok := config.FromContext(ctx); ok {
+		return NewClient(cfg)
+	}
+	return fallback
+}
+
+// Response is a fully buffered Connect API response.
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// Path joins escaped path segments into an API path, e.g.
+// Path("vaults", id, "items") returns "/vaults/{id}/items".
+func Path(segments ...string) string {
+	escaped := make([]string, len(segments))
+	for i, s := range segments {
+		escaped[i] = url.PathEscape(s)
+	}
+	return "/" + strings.Join(escaped, "/")
+}
+
+func (c *Client) Get(ctx context.Context, path string, query url.Values, out any) (*Response, error) {
+	return c.Do(ctx, http.MethodGet, path, query, nil, out)
+}
+
+func (c *Client) Post(ctx context.Context, path string, body, out any) (*Response, error) {
+	return c.Do(ctx, http.MethodPost, path, nil, body, out)
+}
+
+func (c *Client) Put(ctx context.Context, path string, body, out any) (*Response, error) {
+	return c.Do(ctx, http.MethodPut, path, nil, body, out)
+}
+
+func (c *Client) Patch(ctx context.Context, path string, body, out any) (*Response, error) {
+	return c.Do(ctx, http.MethodPatch, path, nil, body, out)
+}
+
+func (c *Client) Delete(ctx context.Context, path string, out any) (*Response, error) {
+	return c.Do(ctx, http.MethodDelete, path, nil, nil, out)
+}
+
+// Do sends a request and buffers the response. A non-nil body is encoded as
+// JSON. When out is non-nil the response is decoded into it: JSON responses
+// are unmarshalled and plain text responses fill a *string or *[]byte.
+// Responses with status >= 400 are returned as *APIError. Transient failures
+// of idempotent requests are retried according to the configured RetryPolicy.
+// A read-only client refuses every method but GET and HEAD with
+// *ReadOnlyError without sending the request, and requests to vaults outside
+// the scope of the configured policy fail with *policy.VaultError.
+func (c *Client) Do(ctx context.Context, method, path string, query url.Values, body, out any) (*Response, error) {
+	if c.readOnly && method != http.MethodGet && method != http.MethodHead {
+		return nil, &ReadOnlyError{Method: method, Path: path}
+	}
+	if err := c.checkVault(ctx, path); err != nil {
+		return nil, err
+	}
+	var result *Response
+	err := c.retry(ctx, method, path, func(ctx context.Context) error {
+		var err error
+		result, err = c.do(ctx, method, path, query, body, out)
+		return err
+	})
+	return result, err
+}
+
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out any) (*Response, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	req, err := c.newRequest(ctx, method, path, query, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	result := &Response{StatusCode: resp.StatusCode, Header: resp.Header, Body: data}
+
+	if resp.StatusCode >= 400 {
+		return result, newAPIError(method, path, resp, data)
+	}
+	if out != nil && len(data) > 0 {
+		if err := decode(resp.Header.Get("Content-Type"), data, out); err != nil {
+			return result, &DecodeError{Body: data, Err: err}
+		}
+	}
+	return result, nil
+}
+
+// Stream sends a GET request and returns the response with its body unread,
+// for payloads that should not be buffered in full. The caller must close the
+// body. Responses with status >= 400 are returned as *APIError. The client's
+// timeout bounds the wait for the response headers only; reading the body is
+// bounded by ctx, so a download can take as long as the caller allows.
+func (c *Client) Stream(ctx context.Context, path string, query url.Values, accept string) (*http.Response, error) {
+	if err := c.checkVault(ctx, path); err != nil {
+		return nil, err
+	}
+	var result *http.Response
+	err := c.retry(ctx, http.MethodGet, path, func(ctx context.Context) error {
+		var err error
+		result, err = c.stream(ctx, path, query, accept)
+		return err
+	})
+	return result, err
+}
+
+func (c *Client) stream(ctx context.Context, path string, query url.Values, accept string) (*http.Response, error) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	req, err := c.newRequest(ctx, http.MethodGet, path, query, nil)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	req.Header.Set("Accept", accept)
+
+	headerTimeout := time.AfterFunc(c.timeout, cancel)
+	resp, err := c.httpClient.Do(req)
+	if !headerTimeout.Stop() {
+		// The timer cancelled the request, possibly just after the headers
+		// arrived; report it the way a deadline on the request would be.
+		if err == nil {
+			resp.Body.Close()
+		}
+		cancel()
+		return nil, fmt.Errorf("request failed: %w", &url.Error{Op: "Get", URL: req.URL.Redacted(), Err: context.DeadlineExceeded})
+	}
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	if resp.StatusCode >= 400 {
+		defer cancel()
+		defer resp.Body.Close()
+		data, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response body: %w", err)
+		}
+		return nil, newAPIError(http.MethodGet, path, resp, data)
+	}
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+func (c *Client) newRequest(ctx context.Context, method, path string, query url.Values, body any) (*http.Request, error) {
+	if c.authErr != nil {
+		return nil, c.authErr
+	}
+
+	u := c.baseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	var reader io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode request body: %w", err)
+		}
+		reader = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("User-Agent", UserAgent)
+	c.auth.Authenticate(req)
+	return req, nil
+}
+
+func decode(contentType string, data []byte, out any) error {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if mediaType != "application/json" {
+		switch v := out.(type) {
+		case *string:
+			*v = string(data)
+			return nil
+		case *[]byte:
+			*v = data
+			return nil
+		}
+	}
+	return json.Unmarshal(data, out)
+}
+
+// cancelOnClose releases a request's context once its streamed body has been
+// closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
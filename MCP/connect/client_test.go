@@ -0,0 +1,74 @@
+package connect
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/1password-connect/mcp-server/config"
+	"github.com/1password-connect/mcp-server/connect/connecttest"
+)
+
+// TestReadOnlyClient checks that a read-only client refuses every method
+// that could modify a vault before it reaches the Connect server.
+func TestReadOnlyClient(t *testing.T) {
+	fake := connecttest.NewServer()
+	defer fake.Close()
+	vault := fake.Store.AddVault("Production", "")
+	cfg := fake.APIConfig()
+	cfg.ReadOnly = true
+	c := NewClient(cfg)
+	ctx := context.Background()
+
+	for _, method := range []string{http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete} {
+		_, err := c.Do(ctx, method, Path("vaults", vault.Id, "items"), nil, nil, nil)
+		var readOnlyErr *ReadOnlyError
+		if !errors.As(err, &readOnlyErr) || readOnlyErr.Method != method {
+			t.Errorf("%s: error = %v, want a *ReadOnlyError", method, err)
+		}
+	}
+	if n := len(fake.Requests()); n != 0 {
+		t.Fatalf("Connect received %d requests, want none", n)
+	}
+
+	if _, err := c.Get(ctx, Path("vaults", vault.Id), nil, nil); err != nil {
+		t.Errorf("GET: %v", err)
+	}
+}
+
+// TestStreamTimeout checks that the client's timeout bounds the wait for the
+// response headers of a stream but not the reading of its body.
+func TestStreamTimeout(t *testing.T) {
+	const timeout = 50 * time.Millisecond
+	headerDelay := make(chan time.Duration, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(<-headerDelay)
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Write([]byte("first "))
+		w.(http.Flusher).Flush()
+		time.Sleep(3 * timeout)
+		w.Write([]byte("second"))
+	}))
+	defer srv.Close()
+	c := NewClient(&config.APIConfig{BaseURL: srv.URL, BearerToken: "t", RequestTimeout: timeout, Retry: config.RetryPolicy{MaxAttempts: 1}})
+
+	headerDelay <- 0
+	resp, err := c.Stream(context.Background(), "/file", nil, "application/octet-stream")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil || string(body) != "first second" {
+		t.Errorf("body = %q, %v; want it read in full past the timeout", body, err)
+	}
+
+	headerDelay <- 3 * timeout
+	if _, err := c.Stream(context.Background(), "/file", nil, "application/octet-stream"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("error = %v, want a deadline error while waiting for headers", err)
+	}
+}
@@ -0,0 +1,477 @@
+// Package connecttest provides a fake 1Password Connect server for hermetic
+// tests. It implements every operation of the Connect API's OpenAPI document
+// against an in-memory Store, and can delay or fail chosen requests.
+package connecttest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/1password-connect/mcp-server/config"
+	"github.com/1password-connect/mcp-server/models"
+	"github.com/google/uuid"
+)
+
+// DefaultToken is the bearer token a Server accepts unless Token is changed.
+const DefaultToken = "connecttest-token"
+
+// Version is the Connect version the Server reports in its health.
+const Version = "1.5.7"
+
+// Server is a fake Connect server listening on a local address. Every
+// endpoint is served both under /v1 and at the root, so either form of base
+// URL works.
+type Server struct {
+	*httptest.Server
+	Store *Store
+	// Token is the bearer token requests to the API must carry. It must not
+	// be changed while requests are being served.
+	Token string
+
+	mu       sync.Mutex
+	faults   []*Fault
+	requests []Request
+	served   map[[2]string]int // Responses by method and status, for /metrics
+}
+
+// Fault makes the requests it matches misbehave: they are delayed by
+// Latency, then answered with Status instead of being served when it is set.
+type Fault struct {
+	Method  string        // Method to match; empty matches every method
+	Path    string        // Prefix of the API paths to match, such as /vaults; empty matches every path
+	Latency time.Duration // Delay before the request is answered
+	Status  int           // Status to fail with, such as 401, 413 or 503; 0 serves the request
+	Message string        // Message of the ErrorResponse; defaults to one fitting Status
+	Times   int           // Number of matching requests affected; 0 affects all of them
+}
+
+// Request is a request the Server received.
+type Request struct {
+	Method string
+	Path   string // API path, without the /v1 prefix
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+}
+
+// NewServer starts a Server with an empty Store. The caller must Close it.
+func NewServer() *Server {
+	s := NewUnstartedServer()
+	s.Start()
+	return s
+}
+
+// NewUnstartedServer returns a Server that is not yet listening, so that
+// its handler in Config.Handler can be wrapped. The caller must Start it and
+// then Close it.
+func NewUnstartedServer() *Server {
+	s := &Server{
+		Store:  NewStore(),
+		Token:  DefaultToken,
+		served: make(map[[2]string]int),
+	}
+	s.Server = httptest.NewUnstartedServer(s.handler())
+	return s
+}
+
+// APIConfig returns the configuration of a client of s. Retries back off
+// for milliseconds only, so that tests of transient failures stay fast.
+func (s *Server) APIConfig() *config.APIConfig {
+	return &config.APIConfig{
+		BaseURL:     s.URL + "/v1",
+		BearerToken: s.Token,
+		Retry: config.RetryPolicy{
+			MaxAttempts: 3,
+			BaseDelay:   time.Millisecond,
+			MaxDelay:    10 * time.Millisecond,
+		},
+	}
+}
+
+// Inject adds f to the faults applied to incoming requests. Faults are
+// checked in the order they were added and only the first match applies.
+func (s *Server) Inject(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &f)
+}
+
+// ClearFaults removes every injected fault.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// Requests returns the requests received so far, oldest first.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.requests)
+}
+
+// public lists the endpoints that need no token.
+var public = []string{"/health", "/heartbeat", "/metrics"}
+
+func (s *Server) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /activity", s.getAPIActivity)
+	mux.HandleFunc("GET /health", s.getServerHealth)
+	mux.HandleFunc("GET /heartbeat", s.getHeartbeat)
+	mux.HandleFunc("GET /metrics", s.getPrometheusMetrics)
+	mux.HandleFunc("GET /vaults", s.getVaults)
+	mux.HandleFunc("GET /vaults/{vaultUuid}", s.getVaultByID)
+	mux.HandleFunc("GET /vaults/{vaultUuid}/items", s.getVaultItems)
+	mux.HandleFunc("POST /vaults/{vaultUuid}/items", s.createVaultItem)
+	mux.HandleFunc("GET /vaults/{vaultUuid}/items/{itemUuid}", s.getVaultItemByID)
+	mux.HandleFunc("PUT /vaults/{vaultUuid}/items/{itemUuid}", s.updateVaultItem)
+	mux.HandleFunc("PATCH /vaults/{vaultUuid}/items/{itemUuid}", s.patchVaultItem)
+	mux.HandleFunc("DELETE /vaults/{vaultUuid}/items/{itemUuid}", s.deleteVaultItem)
+	mux.HandleFunc("GET /vaults/{vaultUuid}/items/{itemUuid}/files", s.getItemFiles)
+	mux.HandleFunc("GET /vaults/{vaultUuid}/items/{itemUuid}/files/{fileUuid}", s.getDetailsOfFileByID)
+	mux.HandleFunc("GET /vaults/{vaultUuid}/items/{itemUuid}/files/{fileUuid}/content", s.downloadFileByID)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if path, ok := strings.CutPrefix(r.URL.Path, "/v1"); ok && strings.HasPrefix(path, "/") {
+			r = r.Clone(r.Context())
+			r.URL.Path = path
+			r.URL.RawPath = ""
+		}
+		body, _ := io.ReadAll(r.Body)
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		s.mu.Lock()
+		s.requests = append(s.requests, Request{
+			Method: r.Method,
+			Path:   r.URL.Path,
+			Query:  r.URL.Query(),
+			Header: r.Header.Clone(),
+			Body:   body,
+		})
+		s.mu.Unlock()
+
+		requestID := uuid.NewString()
+		w.Header().Set("X-Request-Id", requestID)
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		defer func() {
+			s.mu.Lock()
+			s.served[[2]string{r.Method, strconv.Itoa(rec.status)}]++
+			s.mu.Unlock()
+			if strings.HasPrefix(r.URL.Path, "/vaults/") {
+				s.Store.record(s.activity(r, requestID, rec.status))
+			}
+		}()
+
+		if f := s.fault(r); f != nil {
+			if !wait(r.Context(), f.Latency) {
+				return
+			}
+			if f.Status != 0 {
+				message := f.Message
+				if message == "" {
+					message = defaultMessage(f.Status)
+				}
+				writeError(rec, &apiError{Status: f.Status, Message: message})
+				return
+			}
+		}
+		if !slices.Contains(public, r.URL.Path) && r.Header.Get("Authorization") != "Bearer "+s.Token {
+			writeError(rec, &apiError{Status: http.StatusUnauthorized, Message: defaultMessage(http.StatusUnauthorized)})
+			return
+		}
+		mux.ServeHTTP(rec, r)
+	})
+}
+
+// fault returns the fault that applies to r, if any, using up one of its
+// times.
+func (s *Server) fault(r *http.Request) *Fault {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, f := range s.faults {
+		if (f.Method == "" || f.Method == r.Method) && strings.HasPrefix(r.URL.Path, f.Path) {
+			if f.Times > 0 {
+				if f.Times--; f.Times == 0 {
+					s.faults = slices.Delete(s.faults, i, i+1)
+				}
+			}
+			return f
+		}
+	}
+	return nil
+}
+
+// activity describes r for the activity log.
+func (s *Server) activity(r *http.Request, requestID string, status int) models.APIRequest {
+	action := map[string]string{
+		http.MethodPost:   "CREATE",
+		http.MethodPut:    "UPDATE",
+		http.MethodPatch:  "UPDATE",
+		http.MethodDelete: "DELETE",
+	}[r.Method]
+	if action == "" {
+		action = "READ"
+	}
+	result := "SUCCESS"
+	if status == http.StatusUnauthorized || status == http.StatusForbidden {
+		result = "DENY"
+	}
+	resource := map[string]interface{}{"type": "VAULT"}
+	segments := strings.Split(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if len(segments) >= 2 {
+		resource["vault"] = map[string]interface{}{"id": segments[1]}
+	}
+	if len(segments) >= 4 {
+		resource["type"] = "ITEM"
+		resource["item"] = map[string]interface{}{"id": segments[3]}
+		if it, ok := s.Store.Item(segments[1], segments[3]); ok {
+			resource["itemVersion"] = it.Version
+		}
+	}
+	return models.APIRequest{
+		Requestid: requestID,
+		Timestamp: timestamp(),
+		Action:    action,
+		Result:    result,
+		Actor: map[string]interface{}{
+			"id":        Actor,
+			"account":   "CONNECTTEST",
+			"jti":       "connecttest",
+			"requestIp": strings.Split(r.RemoteAddr, ":")[0],
+			"userAgent": r.UserAgent(),
+		},
+		Resource: resource,
+	}
+}
+
+func (s *Server) getAPIActivity(w http.ResponseWriter, r *http.Request) {
+	limit, offset := 50, 0
+	for name, dest := range map[string]*int{"limit": &limit, "offset": &offset} {
+		if v := r.URL.Query().Get(name); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 0 {
+				writeError(w, badRequest("Invalid %s %q", name, v))
+				return
+			}
+			*dest = n
+		}
+	}
+	// The log is served newest first.
+	activity := s.Store.Activity()
+	slices.Reverse(activity)
+	total := len(activity)
+	start, end := min(offset, total), min(offset+limit, total)
+	w.Header().Set("Content-Range", fmt.Sprintf("%d-%d/%d", start+1, end, total))
+	writeJSON(w, http.StatusOK, activity[start:end])
+}
+
+func (s *Server) getServerHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"name":    "1Password Connect API",
+		"version": Version,
+		"dependencies": []models.ServiceDependency{
+			{Service: "sync", Status: "ACTIVE"},
+			{Service: "sqlite", Status: "ACTIVE", Message: "Connected to ./1password.sqlite"},
+		},
+	})
+}
+
+func (s *Server) getHeartbeat(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain")
+	io.WriteString(w, ".")
+}
+
+func (s *Server) getPrometheusMetrics(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	keys := make([][2]string, 0, len(s.served))
+	for key := range s.served {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b [2]string) int { return strings.Compare(a[0]+a[1], b[0]+b[1]) })
+	var buf bytes.Buffer
+	buf.WriteString("# HELP connect_http_requests_total Requests answered by the Connect API, by method and status.\n")
+	buf.WriteString("# TYPE connect_http_requests_total counter\n")
+	for _, key := range keys {
+		fmt.Fprintf(&buf, "connect_http_requests_total{method=%q,status=%q} %d\n", key[0], key[1], s.served[key])
+	}
+	s.mu.Unlock()
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	w.Write(buf.Bytes())
+}
+
+func (s *Server) getVaults(w http.ResponseWriter, r *http.Request) {
+	vaults, err := s.Store.listVaults(r.URL.Query().Get("filter"))
+	respond(w, vaults, err)
+}
+
+func (s *Server) getVaultByID(w http.ResponseWriter, r *http.Request) {
+	vault, err := s.Store.getVault(r.PathValue("vaultUuid"))
+	respond(w, vault, err)
+}
+
+func (s *Server) getVaultItems(w http.ResponseWriter, r *http.Request) {
+	items, err := s.Store.listItems(r.PathValue("vaultUuid"), r.URL.Query().Get("filter"))
+	respond(w, items, err)
+}
+
+func (s *Server) createVaultItem(w http.ResponseWriter, r *http.Request) {
+	var in models.FullItem
+	if err := readJSON(r, &in); err != nil {
+		writeError(w, err)
+		return
+	}
+	it, err := s.Store.AddItem(r.PathValue("vaultUuid"), in)
+	respond(w, it, err)
+}
+
+func (s *Server) getVaultItemByID(w http.ResponseWriter, r *http.Request) {
+	it, err := s.Store.getItem(r.PathValue("vaultUuid"), r.PathValue("itemUuid"))
+	respond(w, it, err)
+}
+
+func (s *Server) updateVaultItem(w http.ResponseWriter, r *http.Request) {
+	var in models.FullItem
+	if err := readJSON(r, &in); err != nil {
+		writeError(w, err)
+		return
+	}
+	it, err := s.Store.replaceItem(r.PathValue("vaultUuid"), r.PathValue("itemUuid"), in)
+	respond(w, it, err)
+}
+
+func (s *Server) patchVaultItem(w http.ResponseWriter, r *http.Request) {
+	var ops models.Patch
+	if err := readJSON(r, &ops); err != nil {
+		writeError(w, err)
+		return
+	}
+	it, err := s.Store.patchItem(r.PathValue("vaultUuid"), r.PathValue("itemUuid"), ops)
+	respond(w, it, err)
+}
+
+func (s *Server) deleteVaultItem(w http.ResponseWriter, r *http.Request) {
+	if err := s.Store.deleteItem(r.PathValue("vaultUuid"), r.PathValue("itemUuid")); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) getItemFiles(w http.ResponseWriter, r *http.Request) {
+	inline, err := inlineFiles(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	files, err := s.Store.listFiles(r.PathValue("vaultUuid"), r.PathValue("itemUuid"), inline)
+	respond(w, files, err)
+}
+
+func (s *Server) getDetailsOfFileByID(w http.ResponseWriter, r *http.Request) {
+	inline, err := inlineFiles(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	f, err := s.Store.getFile(r.PathValue("vaultUuid"), r.PathValue("itemUuid"), r.PathValue("fileUuid"), inline)
+	respond(w, f, err)
+}
+
+func (s *Server) downloadFileByID(w http.ResponseWriter, r *http.Request) {
+	name, content, err := s.Store.fileContent(r.PathValue("vaultUuid"), r.PathValue("itemUuid"), r.PathValue("fileUuid"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+	w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+	w.Write(content)
+}
+
+func inlineFiles(r *http.Request) (bool, error) {
+	v := r.URL.Query().Get("inline_files")
+	if v == "" {
+		return false, nil
+	}
+	inline, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, badRequest("Invalid inline_files %q", v)
+	}
+	return inline, nil
+}
+
+func readJSON(r *http.Request, v any) error {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		return badRequest("Invalid request body: %v", err)
+	}
+	return nil
+}
+
+func respond(w http.ResponseWriter, v any, err error) {
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, v)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	apiErr, ok := err.(*apiError)
+	if !ok {
+		apiErr = &apiError{Status: http.StatusInternalServerError, Message: err.Error()}
+	}
+	writeJSON(w, apiErr.Status, models.ErrorResponse{Status: apiErr.Status, Message: apiErr.Message})
+}
+
+func defaultMessage(status int) string {
+	switch status {
+	case http.StatusUnauthorized:
+		return "Invalid token signature"
+	case http.StatusRequestEntityTooLarge:
+		return "File is too large to inline in request. Use the /v1/vaults/{vaultUUID}/items/{itemUUID}/files/{fileUUID}/content endpoint instead."
+	}
+	return http.StatusText(status)
+}
+
+// wait sleeps for d, or until ctx is done, and reports whether it slept.
+func wait(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return true
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// statusRecorder remembers the status a handler answered with.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
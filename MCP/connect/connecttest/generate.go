@@ -0,0 +1,136 @@
+package connecttest
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/big"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/1password-connect/mcp-server/models"
+)
+
+// Character sets of GeneratorRecipe.characterSets.
+var characterSets = map[string]string{
+	"LETTERS": "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ",
+	"DIGITS":  "0123456789",
+	"SYMBOLS": "!@#$%^&*()-_=+[]{};:,.<>?/",
+}
+
+// generate returns a random value following recipe, like Connect does for
+// fields with generate set. A nil recipe uses Connect's defaults.
+func generate(recipe *models.GeneratorRecipe) (string, error) {
+	if recipe == nil {
+		recipe = &models.GeneratorRecipe{}
+	}
+	length := recipe.Length
+	if length == 0 {
+		length = 32
+	}
+	if length < 1 || length > 64 {
+		return "", badRequest("Invalid recipe length %d: must be between 1 and 64", length)
+	}
+	sets := recipe.Charactersets
+	if len(sets) == 0 {
+		sets = []string{"LETTERS", "DIGITS"}
+	}
+	var alphabet []rune
+	for _, name := range sets {
+		chars, ok := characterSets[name]
+		if !ok {
+			return "", badRequest("Invalid recipe character set %q", name)
+		}
+		for _, r := range chars {
+			if !strings.ContainsRune(recipe.Excludecharacters, r) && !slices.Contains(alphabet, r) {
+				alphabet = append(alphabet, r)
+			}
+		}
+	}
+	if len(alphabet) == 0 {
+		return "", badRequest("Invalid recipe: every character is excluded")
+	}
+	value := make([]rune, length)
+	for i := range value {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(alphabet))))
+		if err != nil {
+			return "", err
+		}
+		value[i] = alphabet[n.Int64()]
+	}
+	return string(value), nil
+}
+
+// entropy estimates the entropy in bits of a password from the classes of
+// characters it uses.
+func entropy(value string) float64 {
+	var lower, upper, digit, symbol bool
+	for _, r := range value {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			symbol = true
+		}
+	}
+	size := 0
+	if lower {
+		size += 26
+	}
+	if upper {
+		size += 26
+	}
+	if digit {
+		size += 10
+	}
+	if symbol {
+		size += len(characterSets["SYMBOLS"])
+	}
+	if size == 0 {
+		return 0
+	}
+	return math.Round(float64(len([]rune(value)))*math.Log2(float64(size))*100) / 100
+}
+
+// totp returns the RFC 6238 one-time password of a TOTP field at t. The
+// value is either an otpauth:// URI or a bare base32 secret; an invalid one
+// yields no password.
+func totp(value string, t time.Time) string {
+	secret, digits, period := value, 6, 30
+	if u, err := url.Parse(value); err == nil && u.Scheme == "otpauth" {
+		q := u.Query()
+		secret = q.Get("secret")
+		fmt.Sscan(q.Get("digits"), &digits)
+		fmt.Sscan(q.Get("period"), &period)
+	}
+	secret = strings.ToUpper(strings.ReplaceAll(secret, " ", ""))
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(strings.TrimRight(secret, "="))
+	if err != nil || len(key) == 0 || digits < 1 || digits > 10 || period < 1 {
+		return ""
+	}
+
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(t.Unix()/int64(period)))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	code := binary.BigEndian.Uint32(sum[offset:]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", digits, uint64(code)%uint64(math.Pow10(digits)))
+}
+
+func encodeBase64(content []byte) string {
+	return base64.StdEncoding.EncodeToString(content)
+}
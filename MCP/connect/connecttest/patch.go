@@ -0,0 +1,152 @@
+package connecttest
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/1password-connect/mcp-server/models"
+)
+
+// applyPatch applies ops to it with Connect's variant of RFC 6902: only add,
+// remove and replace are supported, elements of arrays of objects such as
+// fields may be addressed by their ID as well as by index, adding a value to
+// an array appends it, and the path / stands for the whole item.
+func applyPatch(it models.FullItem, ops models.Patch) (models.FullItem, error) {
+	var doc any
+	if err := convert(it, &doc); err != nil {
+		return models.FullItem{}, err
+	}
+	for _, op := range ops {
+		if !slices.Contains([]string{"add", "remove", "replace"}, op.Op) {
+			return models.FullItem{}, badRequest("Unsupported patch operation %q: only add, remove and replace are supported", op.Op)
+		}
+		if op.Path == "/" || op.Path == "" {
+			if _, ok := op.Value.(map[string]any); !ok || op.Op == "remove" {
+				return models.FullItem{}, badRequest("The whole item can only be replaced with an object")
+			}
+			doc = op.Value
+			continue
+		}
+		if !strings.HasPrefix(op.Path, "/") {
+			return models.FullItem{}, badRequest("Invalid patch path %q", op.Path)
+		}
+		tokens := strings.Split(op.Path[1:], "/")
+		for i, token := range tokens {
+			tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		}
+		var err error
+		if doc, err = apply(doc, tokens, op); err != nil {
+			return models.FullItem{}, err
+		}
+	}
+	var patched models.FullItem
+	if err := convert(doc, &patched); err != nil {
+		return models.FullItem{}, badRequest("Invalid item after patch: %v", err)
+	}
+	return patched, nil
+}
+
+// apply performs op on the value at tokens within node and returns the
+// updated node.
+func apply(node any, tokens []string, op models.PatchOperation) (any, error) {
+	key, last := tokens[0], len(tokens) == 1
+	switch n := node.(type) {
+	case map[string]any:
+		child, exists := n[key]
+		if !last {
+			if !exists {
+				return nil, pathError(op)
+			}
+			updated, err := apply(child, tokens[1:], op)
+			if err != nil {
+				return nil, err
+			}
+			n[key] = updated
+			return n, nil
+		}
+		if !exists && op.Op != "add" {
+			return nil, pathError(op)
+		}
+		switch list, isList := child.([]any); {
+		case op.Op == "remove":
+			delete(n, key)
+		case op.Op == "add" && isList && !isArray(op.Value):
+			n[key] = append(list, op.Value)
+		default:
+			n[key] = op.Value
+		}
+		return n, nil
+
+	case []any:
+		i, err := index(n, key, op, last)
+		if err != nil {
+			return nil, err
+		}
+		if !last {
+			updated, err := apply(n[i], tokens[1:], op)
+			if err != nil {
+				return nil, err
+			}
+			n[i] = updated
+			return n, nil
+		}
+		switch {
+		case op.Op == "remove":
+			return slices.Delete(n, i, i+1), nil
+		case op.Op == "add" && (key == "-" || isIndex(key)):
+			return slices.Insert(n, i, op.Value), nil
+		default:
+			n[i] = op.Value
+			return n, nil
+		}
+	}
+	return nil, pathError(op)
+}
+
+// index returns the position in list that key refers to: an index, - for
+// the end of the list, or the ID of an object in it.
+func index(list []any, key string, op models.PatchOperation, last bool) (int, error) {
+	insert := last && op.Op == "add"
+	if key == "-" && insert {
+		return len(list), nil
+	}
+	if isIndex(key) {
+		i, _ := strconv.Atoi(key)
+		if i < len(list) || (i == len(list) && insert) {
+			return i, nil
+		}
+		return 0, pathError(op)
+	}
+	for i, element := range list {
+		if object, ok := element.(map[string]any); ok && object["id"] == key {
+			return i, nil
+		}
+	}
+	return 0, pathError(op)
+}
+
+func isIndex(key string) bool {
+	_, err := strconv.Atoi(key)
+	return err == nil && !strings.HasPrefix(key, "-")
+}
+
+func isArray(value any) bool {
+	_, ok := value.([]any)
+	return ok
+}
+
+func pathError(op models.PatchOperation) error {
+	return badRequest("Invalid patch path %q: nothing to %s there", op.Path, op.Op)
+}
+
+// convert copies from into to through their JSON encoding.
+func convert(from, to any) error {
+	data, err := json.Marshal(from)
+	if err != nil {
+		return fmt.Errorf("failed to encode %T: %w", from, err)
+	}
+	return json.Unmarshal(data, to)
+}
@@ -0,0 +1,483 @@
+package connecttest
+
+import (
+	"crypto/rand"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/1password-connect/mcp-server/models"
+)
+
+// DefaultInlineLimit is the largest file, in bytes, whose content a Store
+// inlines when inline_files is requested, like OP_MAX_INLINE_FILE_SIZE_KB.
+const DefaultInlineLimit = 100 << 10
+
+// Actor is the ID of the user a Store reports as lastEditedBy and in the
+// activity log.
+const Actor = "6f1c2a9e-4b7d-4e3a-9c58-2d0b7e5a1f34"
+
+// categories are the item categories Connect accepts.
+var categories = []string{
+	"LOGIN", "PASSWORD", "API_CREDENTIAL", "SERVER", "DATABASE", "CREDIT_CARD",
+	"MEMBERSHIP", "PASSPORT", "SOFTWARE_LICENSE", "OUTDOOR_LICENSE", "SECURE_NOTE",
+	"WIRELESS_ROUTER", "BANK_ACCOUNT", "DRIVER_LICENSE", "IDENTITY", "REWARD_PROGRAM",
+	"DOCUMENT", "EMAIL_ACCOUNT", "SOCIAL_SECURITY_NUMBER", "MEDICAL_RECORD", "SSH_KEY",
+	"CUSTOM",
+}
+
+// Store is the in-memory state of a fake Connect server: its vaults, their
+// items and files, and the activity log. It is safe for concurrent use.
+type Store struct {
+	// InlineLimit is the largest file, in bytes, whose content is inlined;
+	// larger files are refused with 413 when inline_files is requested.
+	InlineLimit int
+
+	mu       sync.Mutex
+	vaults   []*vault
+	activity []models.APIRequest
+}
+
+type vault struct {
+	models.Vault
+	items []*item
+}
+
+type item struct {
+	models.FullItem // Without Files, which are kept with their content
+	files           []*file
+}
+
+type file struct {
+	models.File
+	content []byte
+}
+
+// apiError is a failure the fake answers with as an ErrorResponse.
+type apiError struct {
+	Status  int
+	Message string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("%d: %s", e.Status, e.Message)
+}
+
+func badRequest(format string, args ...any) error {
+	return &apiError{Status: http.StatusBadRequest, Message: fmt.Sprintf(format, args...)}
+}
+
+// NewStore returns an empty Store.
+func NewStore() *Store {
+	return &Store{InlineLimit: DefaultInlineLimit}
+}
+
+// AddVault adds an empty vault named name and returns it.
+func (s *Store) AddVault(name, description string) models.Vault {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := timestamp()
+	v := &vault{Vault: models.Vault{
+		Id:               newID(),
+		Name:             name,
+		Description:      description,
+		TypeField:        "USER_CREATED",
+		Attributeversion: 1,
+		Contentversion:   1,
+		Createdat:        now,
+		Updatedat:        now,
+	}}
+	s.vaults = append(s.vaults, v)
+	return v.Vault
+}
+
+// AddItem adds item to the vault vaultID as if it had been created through
+// the API, and returns it as stored.
+func (s *Store) AddItem(vaultID string, it models.FullItem) (models.FullItem, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.createItem(vaultID, it)
+}
+
+// AddFile attaches a file named name to an item and returns its details.
+func (s *Store) AddFile(vaultID, itemID, name string, content []byte) (models.File, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, it, err := s.item(vaultID, itemID)
+	if err != nil {
+		return models.File{}, err
+	}
+	f := &file{content: content, File: models.File{
+		Id:   newID(),
+		Name: name,
+		Size: len(content),
+	}}
+	f.Content_path = fmt.Sprintf("v1/vaults/%s/items/%s/files/%s/content", vaultID, itemID, f.Id)
+	it.files = append(it.files, f)
+	s.touch(v, it)
+	return f.File, nil
+}
+
+// Item returns the item itemID of the vault vaultID as the API would.
+func (s *Store) Item(vaultID, itemID string) (models.FullItem, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, it, err := s.item(vaultID, itemID)
+	if err != nil {
+		return models.FullItem{}, false
+	}
+	return it.view(), true
+}
+
+// Activity returns the requests recorded so far, oldest first.
+func (s *Store) Activity() []models.APIRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.activity)
+}
+
+func (s *Store) record(req models.APIRequest) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.activity = append(s.activity, req)
+}
+
+func (s *Store) listVaults(filter string) ([]models.Vault, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	match, err := parseFilter(filter, "name")
+	if err != nil {
+		return nil, err
+	}
+	vaults := []models.Vault{}
+	for _, v := range s.vaults {
+		if match(v.Name) {
+			vaults = append(vaults, v.summary())
+		}
+	}
+	return vaults, nil
+}
+
+func (s *Store) getVault(vaultID string) (models.Vault, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, err := s.vault(vaultID)
+	if err != nil {
+		return models.Vault{}, err
+	}
+	return v.summary(), nil
+}
+
+func (s *Store) listItems(vaultID, filter string) ([]models.Item, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, err := s.vault(vaultID)
+	if err != nil {
+		return nil, err
+	}
+	match, err := parseFilter(filter, "title")
+	if err != nil {
+		return nil, err
+	}
+	items := []models.Item{}
+	for _, it := range v.items {
+		if match(it.Title) {
+			items = append(items, it.summary())
+		}
+	}
+	return items, nil
+}
+
+func (s *Store) getItem(vaultID, itemID string) (models.FullItem, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, it, err := s.item(vaultID, itemID)
+	if err != nil {
+		return models.FullItem{}, err
+	}
+	return it.view(), nil
+}
+
+func (s *Store) createItem(vaultID string, in models.FullItem) (models.FullItem, error) {
+	v, err := s.vault(vaultID)
+	if err != nil {
+		return models.FullItem{}, err
+	}
+	it := &item{}
+	it.Id = newID()
+	it.Createdat = timestamp()
+	if err := it.set(v, in); err != nil {
+		return models.FullItem{}, err
+	}
+	v.items = append(v.items, it)
+	s.touch(v, it)
+	return it.view(), nil
+}
+
+func (s *Store) replaceItem(vaultID, itemID string, in models.FullItem) (models.FullItem, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, it, err := s.item(vaultID, itemID)
+	if err != nil {
+		return models.FullItem{}, err
+	}
+	if in.Id != "" && in.Id != itemID {
+		return models.FullItem{}, badRequest("item ID %s does not match the path", in.Id)
+	}
+	if err := it.set(v, in); err != nil {
+		return models.FullItem{}, err
+	}
+	s.touch(v, it)
+	return it.view(), nil
+}
+
+func (s *Store) patchItem(vaultID, itemID string, ops models.Patch) (models.FullItem, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, it, err := s.item(vaultID, itemID)
+	if err != nil {
+		return models.FullItem{}, err
+	}
+	if len(ops) == 0 {
+		return it.view(), nil
+	}
+	patched, err := applyPatch(it.FullItem, ops)
+	if err != nil {
+		return models.FullItem{}, err
+	}
+	if err := it.set(v, patched); err != nil {
+		return models.FullItem{}, err
+	}
+	s.touch(v, it)
+	return it.view(), nil
+}
+
+func (s *Store) deleteItem(vaultID, itemID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, it, err := s.item(vaultID, itemID)
+	if err != nil {
+		return err
+	}
+	v.items = slices.DeleteFunc(v.items, func(other *item) bool { return other == it })
+	v.Contentversion++
+	v.Updatedat = timestamp()
+	return nil
+}
+
+func (s *Store) listFiles(vaultID, itemID string, inline bool) ([]models.File, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, it, err := s.item(vaultID, itemID)
+	if err != nil {
+		return nil, err
+	}
+	files := []models.File{}
+	for _, f := range it.files {
+		view, err := s.fileView(f, inline)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, view)
+	}
+	return files, nil
+}
+
+func (s *Store) getFile(vaultID, itemID, fileID string, inline bool) (models.File, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, err := s.file(vaultID, itemID, fileID)
+	if err != nil {
+		return models.File{}, err
+	}
+	return s.fileView(f, inline)
+}
+
+func (s *Store) fileContent(vaultID, itemID, fileID string) (string, []byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, err := s.file(vaultID, itemID, fileID)
+	if err != nil {
+		return "", nil, err
+	}
+	return f.Name, f.content, nil
+}
+
+func (s *Store) fileView(f *file, inline bool) (models.File, error) {
+	view := f.File
+	if inline {
+		if len(f.content) > s.InlineLimit {
+			status := http.StatusRequestEntityTooLarge
+			return models.File{}, &apiError{Status: status, Message: defaultMessage(status)}
+		}
+		view.Content = encodeBase64(f.content)
+	}
+	return view, nil
+}
+
+func (s *Store) vault(vaultID string) (*vault, error) {
+	for _, v := range s.vaults {
+		if v.Id == vaultID {
+			return v, nil
+		}
+	}
+	return nil, &apiError{Status: http.StatusNotFound, Message: fmt.Sprintf("vault %s not found", vaultID)}
+}
+
+func (s *Store) item(vaultID, itemID string) (*vault, *item, error) {
+	v, err := s.vault(vaultID)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, it := range v.items {
+		if it.Id == itemID {
+			return v, it, nil
+		}
+	}
+	return nil, nil, &apiError{Status: http.StatusNotFound, Message: fmt.Sprintf("item %s not found", itemID)}
+}
+
+func (s *Store) file(vaultID, itemID, fileID string) (*file, error) {
+	_, it, err := s.item(vaultID, itemID)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range it.files {
+		if f.Id == fileID {
+			return f, nil
+		}
+	}
+	return nil, &apiError{Status: http.StatusNotFound, Message: fmt.Sprintf("file %s not found", fileID)}
+}
+
+// touch records a change to it: a new version of the item and of the
+// contents of its vault.
+func (s *Store) touch(v *vault, it *item) {
+	now := timestamp()
+	it.Version++
+	it.Updatedat = now
+	it.Lasteditedby = Actor
+	v.Contentversion++
+	v.Updatedat = now
+}
+
+func (v *vault) summary() models.Vault {
+	summary := v.Vault
+	summary.Items = len(v.items)
+	return summary
+}
+
+// set replaces the writable attributes of it with those of in, the way
+// Connect validates and completes an item on create and update.
+func (it *item) set(v *vault, in models.FullItem) error {
+	if !slices.Contains(categories, in.Category) {
+		return badRequest("Invalid item category")
+	}
+	if id, ok := in.Vault["id"]; ok && id != v.Id {
+		return badRequest("item vault %v does not match the vault in the path", id)
+	}
+	fields := make([]models.Field, len(in.Fields))
+	for i, f := range in.Fields {
+		if f.Id == "" {
+			f.Id = newID()
+		}
+		if f.TypeField == "" {
+			f.TypeField = "STRING"
+		}
+		if f.Generate && f.Value == "" {
+			value, err := generate(f.Recipe)
+			if err != nil {
+				return err
+			}
+			f.Value = value
+		}
+		f.Generate = false
+		f.Recipe = nil
+		f.Entropy = 0
+		f.Totp = ""
+		if f.Purpose == "PASSWORD" || f.TypeField == "CONCEALED" {
+			f.Entropy = entropy(f.Value)
+		}
+		fields[i] = f
+	}
+	it.Title = in.Title
+	it.Category = in.Category
+	it.Favorite = in.Favorite
+	it.Tags = in.Tags
+	it.Urls = in.Urls
+	it.Sections = in.Sections
+	it.Fields = fields
+	it.Vault = map[string]interface{}{"id": v.Id}
+	return nil
+}
+
+func (it *item) summary() models.Item {
+	return models.Item{
+		Id:           it.Id,
+		Title:        it.Title,
+		Vault:        it.Vault,
+		Category:     it.Category,
+		Urls:         it.Urls,
+		Favorite:     it.Favorite,
+		Tags:         it.Tags,
+		Version:      it.Version,
+		State:        it.State,
+		Createdat:    it.Createdat,
+		Updatedat:    it.Updatedat,
+		Lasteditedby: it.Lasteditedby,
+	}
+}
+
+// view returns the item as the API presents it, with its files and the
+// current one-time passwords of its TOTP fields.
+func (it *item) view() models.FullItem {
+	full := it.FullItem
+	full.Fields = slices.Clone(it.Fields)
+	for i, f := range full.Fields {
+		if f.TypeField == "TOTP" {
+			full.Fields[i].Totp = totp(f.Value, time.Now())
+		}
+	}
+	for _, f := range it.files {
+		full.Files = append(full.Files, f.File)
+	}
+	return full
+}
+
+// filterPattern matches the SCIM `attr eq "value"` filters Connect accepts.
+var filterPattern = regexp.MustCompile(`^\s*(\w+)\s+eq\s+"((?:[^"\\]|\\.)*)"\s*$`)
+
+// parseFilter returns a predicate for the list filter, which may only
+// compare attr. Like Connect, names are compared case-insensitively.
+func parseFilter(filter, attr string) (func(string) bool, error) {
+	if filter == "" {
+		return func(string) bool { return true }, nil
+	}
+	m := filterPattern.FindStringSubmatch(filter)
+	if m == nil || m[1] != attr {
+		return nil, badRequest("Invalid filter %q: only %s eq \"...\" is supported", filter, attr)
+	}
+	value := strings.NewReplacer(`\\`, `\`, `\"`, `"`).Replace(m[2])
+	return func(s string) bool { return strings.EqualFold(s, value) }, nil
+}
+
+// idAlphabet holds the characters of Connect IDs.
+const idAlphabet = "abcdefghijklmnopqrstuvwxyz0123456789"
+
+// newID returns a random 26 character ID in the format Connect uses.
+func newID() string {
+	b := make([]byte, 26)
+	rand.Read(b)
+	for i := range b {
+		b[i] = idAlphabet[int(b[i])%len(idAlphabet)]
+	}
+	return string(b)
+}
+
+func timestamp() string {
+	return time.Now().UTC().Format(time.RFC3339Nano)
+}
@@ -0,0 +1,194 @@
+package connect
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/1password-connect/mcp-server/models"
+)
+
+// ErrorKind classifies a failed Connect call so that agents can react to it
+// without parsing messages.
+type ErrorKind string
+
+const (
+	KindBadRequest   ErrorKind = "bad_request"
+	KindUnauthorized ErrorKind = "unauthorized"
+	KindForbidden    ErrorKind = "forbidden"
+	KindNotFound     ErrorKind = "not_found"
+	KindTooLarge     ErrorKind = "too_large"
+	KindRateLimited  ErrorKind = "rate_limited"
+	KindServer       ErrorKind = "server_error"
+	KindReadOnly     ErrorKind = "read_only"
+	KindDecode       ErrorKind = "decode_error"
+	KindUnknown      ErrorKind = "unknown"
+)
+
+// requestIDHeaders are the response headers a Connect server or a proxy in
+// front of it may use to identify a request.
+var requestIDHeaders = []string{"X-Request-Id", "X-Correlation-Id", "Request-Id"}
+
+// APIError is returned when the Connect server answers with a status >= 400.
+type APIError struct {
+	Kind       ErrorKind
+	StatusCode int
+	Message    string // ErrorResponse.message, or the raw body when it is not JSON
+	RequestID  string
+	Method     string
+	Path       string
+	RetryAfter string
+	Attempts   int // Number of times the request was sent, when retried
+	Body       []byte
+}
+
+func newAPIError(method, path string, resp *http.Response, body []byte) *APIError {
+	e := &APIError{
+		Kind:       classify(resp.StatusCode),
+		StatusCode: resp.StatusCode,
+		Method:     method,
+		Path:       path,
+		RetryAfter: resp.Header.Get("Retry-After"),
+		Body:       body,
+	}
+	var errResp models.ErrorResponse
+	if err := json.Unmarshal(body, &errResp); err == nil && errResp.Message != "" {
+		e.Message = errResp.Message
+	} else {
+		e.Message = strings.TrimSpace(string(body))
+	}
+	if e.Message == "" {
+		e.Message = http.StatusText(resp.StatusCode)
+	}
+	for _, h := range requestIDHeaders {
+		if id := resp.Header.Get(h); id != "" {
+			e.RequestID = id
+			break
+		}
+	}
+	return e
+}
+
+func classify(status int) ErrorKind {
+	switch {
+	case status == http.StatusUnauthorized:
+		return KindUnauthorized
+	case status == http.StatusForbidden:
+		return KindForbidden
+	case status == http.StatusNotFound:
+		return KindNotFound
+	case status == http.StatusRequestEntityTooLarge:
+		return KindTooLarge
+	case status == http.StatusTooManyRequests:
+		return KindRateLimited
+	case status >= 500:
+		return KindServer
+	case status >= 400:
+		return KindBadRequest
+	}
+	return KindUnknown
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("API error (%s, %d): %s", e.Kind, e.StatusCode, e.Message)
+	if hint := e.Hint(); hint != "" {
+		msg += ". " + hint
+	}
+	if e.RequestID != "" {
+		msg += fmt.Sprintf(" (request ID: %s)", e.RequestID)
+	}
+	if e.Attempts > 1 {
+		msg += fmt.Sprintf(" (after %d attempts)", e.Attempts)
+	}
+	return msg
+}
+
+// Hint returns an actionable explanation of the error.
+func (e *APIError) Hint() string {
+	vault, item := e.pathIDs()
+	switch e.Kind {
+	case KindUnauthorized:
+		return "The Connect credentials are missing, invalid or expired; check the configured token"
+	case KindForbidden:
+		if vault != "" {
+			return fmt.Sprintf("The token lacks access to vault %s", vault)
+		}
+		return "The token is not permitted to perform this operation"
+	case KindNotFound:
+		switch {
+		case item != "":
+			return fmt.Sprintf("Check that item %s exists in vault %s and that the token can see it", item, vault)
+		case vault != "":
+			return fmt.Sprintf("Check that vault %s exists and that the token can see it", vault)
+		}
+	case KindTooLarge:
+		if strings.Contains(e.Path+"/", "/files/") {
+			return "The file is too large to inline; download it with get_vaults_vaultUuid_items_itemUuid_files_fileUuid_content instead"
+		}
+		return "The request or response is too large for the Connect server; send or ask for less data"
+	case KindRateLimited:
+		if e.RetryAfter != "" {
+			return fmt.Sprintf("The Connect server is rate limiting requests; retry after %s seconds", e.RetryAfter)
+		}
+		return "The Connect server is rate limiting requests; retry later"
+	case KindServer:
+		return "The Connect server failed to handle the request, possibly while syncing with 1Password.com; try again shortly"
+	}
+	return ""
+}
+
+// pathIDs extracts the vault and item IDs from a request path of the form
+// /vaults/{vault}/items/{item}/...
+func (e *APIError) pathIDs() (vault, item string) {
+	parts := strings.Split(strings.Trim(e.Path, "/"), "/")
+	if len(parts) >= 2 && parts[0] == "vaults" {
+		vault = parts[1]
+	}
+	if len(parts) >= 4 && parts[2] == "items" {
+		item = parts[3]
+	}
+	return vault, item
+}
+
+// DecodeError is returned when a successful response cannot be decoded into
+// the requested type. Body holds the raw response, for debugging only: the
+// redaction policy never saw it, so it must not be shown to callers.
+type DecodeError struct {
+	Body []byte
+	Err  error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("failed to decode response: %v", e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// ReadOnlyError is returned by a read-only client for a request that could
+// modify a vault.
+type ReadOnlyError struct {
+	Method string
+	Path   string
+}
+
+func (e *ReadOnlyError) Error() string {
+	return fmt.Sprintf("%s %s refused: the server is running in read-only mode", e.Method, e.Path)
+}
+
+// SizeError is returned when a response body is larger than the caller is
+// willing to buffer. Size is 0 when the server did not announce the size.
+type SizeError struct {
+	Path  string
+	Size  int64
+	Limit int64
+}
+
+func (e *SizeError) Error() string {
+	if e.Size > 0 {
+		return fmt.Sprintf("File is %d bytes, which exceeds the maximum download size of %d bytes", e.Size, e.Limit)
+	}
+	return fmt.Sprintf("File exceeds the maximum download size of %d bytes", e.Limit)
+}
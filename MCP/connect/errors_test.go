@@ -0,0 +1,24 @@
+package connect
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTooLargeHint(t *testing.T) {
+	tests := []struct {
+		method, path string
+		want         string
+	}{
+		{"GET", "/vaults/v/items/i/files", "download it with"},
+		{"GET", "/vaults/v/items/i/files/f", "download it with"},
+		{"POST", "/vaults/v/items", "request or response is too large"},
+		{"PUT", "/vaults/v/items/i", "request or response is too large"},
+	}
+	for _, tt := range tests {
+		e := &APIError{Kind: KindTooLarge, StatusCode: 413, Method: tt.method, Path: tt.path}
+		if hint := e.Hint(); !strings.Contains(hint, tt.want) {
+			t.Errorf("%s %s: hint = %q, want it to mention %q", tt.method, tt.path, hint, tt.want)
+		}
+	}
+}
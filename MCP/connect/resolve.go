@@ -0,0 +1,169 @@
+package connect
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/1password-connect/mcp-server/models"
+	"github.com/1password-connect/mcp-server/policy"
+)
+
+// KindAmbiguous classifies a name that matches more than one vault or item.
+const KindAmbiguous ErrorKind = "ambiguous"
+
+// Candidate is one of several vaults or items matching a name.
+type Candidate struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// ResolveError is returned when a vault or item name matches nothing, or more
+// than one thing.
+type ResolveError struct {
+	Kind       ErrorKind // KindNotFound or KindAmbiguous
+	Resource   string    // "vault" or "item"
+	Name       string
+	Attribute  string // What Name was matched against; "name" when empty
+	Candidates []Candidate
+}
+
+func (e *ResolveError) Error() string {
+	if e.Kind == KindNotFound {
+		if e.Attribute != "" {
+			return fmt.Sprintf("no %s with %s %q", e.Resource, e.Attribute, e.Name)
+		}
+		return fmt.Sprintf("no %s named %q", e.Resource, e.Name)
+	}
+	matches := make([]string, len(e.Candidates))
+	for i, c := range e.Candidates {
+		matches[i] = fmt.Sprintf("%s (%s)", c.Name, c.ID)
+	}
+	return fmt.Sprintf("%s %s %q is ambiguous; it matches %s", e.Resource, e.attribute(), e.Name, strings.Join(matches, ", "))
+}
+
+// Hint returns an actionable explanation of the error.
+func (e *ResolveError) Hint() string {
+	if e.Kind == KindNotFound {
+		return fmt.Sprintf("Check the %s %s, or pass its ID instead", e.Resource, e.attribute())
+	}
+	return fmt.Sprintf("Pass the ID of the intended %s instead of its %s", e.Resource, e.attribute())
+}
+
+func (e *ResolveError) attribute() string {
+	if e.Attribute == "" {
+		return "name"
+	}
+	return e.Attribute
+}
+
+// ResolveVault returns the ID of the vault identified by vault, which is
+// either an ID or a vault name. A name that looks like an ID is looked up
+// as a name when no vault has it as its ID.
+func (c *Client) ResolveVault(ctx context.Context, vault string) (string, error) {
+	if models.IsUUID(vault) {
+		_, err := c.Get(ctx, Path("vaults", vault), nil, nil)
+		if !isNotFound(err) {
+			return vault, err
+		}
+		id, nameErr := c.resolveVaultName(ctx, vault)
+		if isNotFoundName(nameErr) {
+			return "", err
+		}
+		return id, nameErr
+	}
+	return c.resolveVaultName(ctx, vault)
+}
+
+func (c *Client) resolveVaultName(ctx context.Context, vault string) (string, error) {
+	var vaults []models.Vault
+	if _, err := c.Get(ctx, Path("vaults"), scimFilter("name", vault), &vaults); err != nil {
+		return "", err
+	}
+	// Vaults outside the policy's scope are not offered as candidates, but
+	// naming one explicitly is reported as a policy violation.
+	if visible := c.FilterVaults(ctx, vaults); len(visible) < len(vaults) {
+		if len(visible) == 0 {
+			return "", &policy.VaultError{Tool: policy.ToolFrom(ctx), Vault: strconv.Quote(vault)}
+		}
+		vaults = visible
+	}
+	candidates := make([]Candidate, len(vaults))
+	for i, v := range vaults {
+		candidates[i] = Candidate{ID: v.Id, Name: v.Name}
+	}
+	return pick("vault", vault, candidates)
+}
+
+// ResolveItem returns the ID of the item identified by item within the vault
+// vaultID, where item is either an ID or an item title. A title that looks
+// like an ID is looked up as a title when no item has it as its ID.
+func (c *Client) ResolveItem(ctx context.Context, vaultID, item string) (string, error) {
+	if models.IsUUID(item) {
+		_, err := c.Get(ctx, Path("vaults", vaultID, "items", item), nil, nil)
+		if !isNotFound(err) {
+			return item, err
+		}
+		id, titleErr := c.resolveItemTitle(ctx, vaultID, item)
+		if isNotFoundName(titleErr) {
+			return "", err
+		}
+		return id, titleErr
+	}
+	return c.resolveItemTitle(ctx, vaultID, item)
+}
+
+func (c *Client) resolveItemTitle(ctx context.Context, vaultID, item string) (string, error) {
+	var items []models.Item
+	if _, err := c.Get(ctx, Path("vaults", vaultID, "items"), scimFilter("title", item), &items); err != nil {
+		return "", err
+	}
+	candidates := make([]Candidate, len(items))
+	for i, it := range items {
+		candidates[i] = Candidate{ID: it.Id, Name: it.Title}
+	}
+	return pick("item", item, candidates)
+}
+
+// isNotFound reports whether err is Connect's 404 for a missing vault or item.
+func isNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.Kind == KindNotFound
+}
+
+// isNotFoundName reports whether err is the failure to find a name.
+func isNotFoundName(err error) bool {
+	var resolveErr *ResolveError
+	return errors.As(err, &resolveErr) && resolveErr.Kind == KindNotFound
+}
+
+// scimFilter builds the `attr eq "value"` filter Connect accepts on its list
+// endpoints.
+func scimFilter(attr, value string) url.Values {
+	escaped := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value)
+	return url.Values{"filter": {fmt.Sprintf(`%s eq "%s"`, attr, escaped)}}
+}
+
+// pick chooses the single candidate matching name. When the server's filter
+// matched several, an exact, case-sensitive match among them still wins.
+func pick(resource, name string, candidates []Candidate) (string, error) {
+	if len(candidates) == 1 {
+		return candidates[0].ID, nil
+	}
+	if len(candidates) == 0 {
+		return "", &ResolveError{Kind: KindNotFound, Resource: resource, Name: name}
+	}
+	var exact []Candidate
+	for _, c := range candidates {
+		if c.Name == name {
+			exact = append(exact, c)
+		}
+	}
+	if len(exact) == 1 {
+		return exact[0].ID, nil
+	}
+	return "", &ResolveError{Kind: KindAmbiguous, Resource: resource, Name: name, Candidates: candidates}
+}
@@ -0,0 +1,122 @@
+package connect
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/1password-connect/mcp-server/connect/connecttest"
+	"github.com/1password-connect/mcp-server/models"
+)
+
+// idLikeName is a name with the shape of a Connect ID.
+const idLikeName = "abcdefghijklmnopqrstuvwxyz"
+
+func TestResolveVault(t *testing.T) {
+	fake := connecttest.NewServer()
+	defer fake.Close()
+	production := fake.Store.AddVault("Production", "")
+	shared := fake.Store.AddVault("Shared", "")
+	sharedLower := fake.Store.AddVault("shared", "")
+	idLike := fake.Store.AddVault(idLikeName, "")
+	c := NewClient(fake.APIConfig())
+
+	tests := []struct {
+		vault      string
+		want       string
+		kind       ErrorKind // Set when vault does not resolve
+		candidates int
+	}{
+		{vault: production.Id, want: production.Id},
+		{vault: "Production", want: production.Id},
+		{vault: "PRODUCTION", want: production.Id},
+		{vault: "Shared", want: shared.Id},
+		{vault: "shared", want: sharedLower.Id},
+		{vault: idLikeName, want: idLike.Id},
+		{vault: "SHARED", kind: KindAmbiguous, candidates: 2},
+		{vault: "Development", kind: KindNotFound},
+		{vault: "zzzzzzzzzzzzzzzzzzzzzzzzzz", kind: KindNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.vault, func(t *testing.T) {
+			got, err := c.ResolveVault(context.Background(), tt.vault)
+			checkResolved(t, got, err, tt.want, tt.kind, tt.candidates)
+		})
+	}
+}
+
+func TestResolveItem(t *testing.T) {
+	fake := connecttest.NewServer()
+	defer fake.Close()
+	vault := fake.Store.AddVault("Production", "")
+	add := func(title string) models.FullItem {
+		it, err := fake.Store.AddItem(vault.Id, models.FullItem{Title: title, Category: "LOGIN"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return it
+	}
+	database := add("Database")
+	first := add("Backup")
+	second := add("Backup")
+	idLike := add(idLikeName)
+	c := NewClient(fake.APIConfig())
+
+	tests := []struct {
+		item       string
+		want       string
+		kind       ErrorKind
+		candidates int
+	}{
+		{item: database.Id, want: database.Id},
+		{item: "database", want: database.Id},
+		{item: idLikeName, want: idLike.Id},
+		{item: "Backup", kind: KindAmbiguous, candidates: 2},
+		{item: "Cache", kind: KindNotFound},
+		{item: "zzzzzzzzzzzzzzzzzzzzzzzzzz", kind: KindNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.item, func(t *testing.T) {
+			got, err := c.ResolveItem(context.Background(), vault.Id, tt.item)
+			checkResolved(t, got, err, tt.want, tt.kind, tt.candidates)
+		})
+	}
+	_, err := c.ResolveItem(context.Background(), vault.Id, "Backup")
+	var resolveErr *ResolveError
+	if errors.As(err, &resolveErr) {
+		ids := []string{resolveErr.Candidates[0].ID, resolveErr.Candidates[1].ID}
+		if !(ids[0] == first.Id && ids[1] == second.Id) && !(ids[0] == second.Id && ids[1] == first.Id) {
+			t.Errorf("candidates = %+v, want both backups", resolveErr.Candidates)
+		}
+	}
+}
+
+// checkResolved checks the outcome of a resolution: the ID want, or an error
+// of kind with the given number of candidates. A missing name fails with a
+// *ResolveError, a missing ID with Connect's *APIError.
+func checkResolved(t *testing.T, got string, err error, want string, kind ErrorKind, candidates int) {
+	t.Helper()
+	if kind == "" {
+		if err != nil || got != want {
+			t.Errorf("resolved to %q, %v; want %q", got, err, want)
+		}
+		return
+	}
+	var resolveErr *ResolveError
+	var apiErr *APIError
+	switch {
+	case errors.As(err, &resolveErr):
+		if resolveErr.Kind != kind || len(resolveErr.Candidates) != candidates {
+			t.Errorf("error = %v with %d candidates, want %s with %d", err, len(resolveErr.Candidates), kind, candidates)
+		}
+		if resolveErr.Hint() == "" {
+			t.Error("error has no hint")
+		}
+	case errors.As(err, &apiErr):
+		if apiErr.Kind != kind || kind != KindNotFound {
+			t.Errorf("error = %v, want %s", err, kind)
+		}
+	default:
+		t.Errorf("resolved to %q, %v; want a %s error", got, err, kind)
+	}
+}
@@ -0,0 +1,235 @@
+package connect
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/1password-connect/mcp-server/config"
+)
+
+// retry runs attempt until it succeeds, fails in a way that is not worth
+// retrying, the policy runs out of attempts, or ctx is done. A retry whose
+// backoff would outlast ctx's deadline is not started.
+func (c *Client) retry(ctx context.Context, method, path string, attempt func(context.Context) error) error {
+	counter := counterFrom(ctx)
+	for n := 1; ; n++ {
+		counter.requests.Add(1)
+		err := attempt(ctx)
+		if err == nil || n >= c.retryPolicy.MaxAttempts || !retryable(ctx, method, err) {
+			return withAttempts(err, n)
+		}
+		delay := c.backoff(n, err)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return withAttempts(err, n)
+		}
+		log.Printf("Connect %s %s failed on attempt %d/%d, retrying in %s: %v",
+			method, path, n, c.retryPolicy.MaxAttempts, delay.Round(time.Millisecond), err)
+		if err := sleep(ctx, delay); err != nil {
+			return err
+		}
+		counter.retries.Add(1)
+	}
+}
+
+// retryable reports whether a failed request may be sent again. Only
+// idempotent methods are retried, PATCH only under WithIdempotentPatch, and
+// nothing under WithoutRetries.
+func retryable(ctx context.Context, method string, err error) bool {
+	if once, _ := ctx.Value(withoutRetriesKey{}).(bool); once {
+		return false
+	}
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+	case http.MethodPatch:
+		if idempotent, _ := ctx.Value(idempotentPatchKey{}).(bool); !idempotent {
+			return false
+		}
+	default:
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+			http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+	return isTransportError(ctx, err)
+}
+
+// backoff returns the wait before attempt n+1: exponential in n, capped at
+// the policy's MaxDelay, partly randomised, and never shorter than a
+// Retry-After the server sent.
+func (c *Client) backoff(n int, err error) time.Duration {
+	policy := c.retryPolicy
+	delay := policy.MaxDelay
+	if shift := n - 1; shift < 30 && policy.BaseDelay<<shift < policy.MaxDelay {
+		delay = policy.BaseDelay << shift
+	}
+	delay -= time.Duration(policy.Jitter * rand.Float64() * float64(delay))
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		if after, ok := parseRetryAfter(apiErr.RetryAfter); ok && after > delay {
+			delay = after
+		}
+	}
+	return delay
+}
+
+// parseRetryAfter accepts both forms of the Retry-After header: a number of
+// seconds or an HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return max(time.Until(t), 0), true
+	}
+	return 0, false
+}
+
+// isTransportError reports whether err is a connection-level failure rather
+// than a response from the server or a cancellation by the caller. A
+// per-attempt timeout counts as a transport error while ctx is still live.
+func isTransportError(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
+
+// sleep waits for d or until ctx is done, whichever comes first.
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// retryPolicy fills in defaults for an unset or partial policy.
+func retryPolicy(p config.RetryPolicy) config.RetryPolicy {
+	def := config.DefaultRetryPolicy
+	if p.MaxAttempts < 1 {
+		p.MaxAttempts = def.MaxAttempts
+	}
+	if p.BaseDelay <= 0 {
+		p.BaseDelay = def.BaseDelay
+	}
+	if p.MaxDelay <= 0 {
+		p.MaxDelay = def.MaxDelay
+	}
+	p.Jitter = min(max(p.Jitter, 0), 1)
+	return p
+}
+
+type idempotentPatchKey struct{}
+
+// WithIdempotentPatch marks PATCH requests made with the returned context as
+// safe to retry. Callers must only use it for patches whose repeated
+// application leaves the item unchanged, such as ones made only of replace
+// operations that generate no values.
+func WithIdempotentPatch(ctx context.Context) context.Context {
+	return context.WithValue(ctx, idempotentPatchKey{}, true)
+}
+
+type withoutRetriesKey struct{}
+
+// WithoutRetries marks requests made with the returned context as unsafe to
+// repeat, whatever their method. Callers use it for requests that have
+// Connect generate values, since every attempt would generate new ones.
+func WithoutRetries(ctx context.Context) context.Context {
+	return context.WithValue(ctx, withoutRetriesKey{}, true)
+}
+
+// attemptsError records how many attempts were made before a request failed.
+type attemptsError struct {
+	err      error
+	attempts int
+}
+
+func (e *attemptsError) Error() string {
+	return fmt.Sprintf("%v (after %d attempts)", e.err, e.attempts)
+}
+
+func (e *attemptsError) Unwrap() error {
+	return e.err
+}
+
+func withAttempts(err error, n int) error {
+	if err == nil || n <= 1 {
+		return err
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		apiErr.Attempts = n
+		return err
+	}
+	return &attemptsError{err: err, attempts: n}
+}
+
+// Attempts returns how many times the request that produced err was sent if
+// it was retried, and 0 otherwise.
+func Attempts(err error) int {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.Attempts > 0 {
+		return apiErr.Attempts
+	}
+	var attemptsErr *attemptsError
+	if errors.As(err, &attemptsErr) {
+		return attemptsErr.attempts
+	}
+	return 0
+}
+
+// AttemptCounter counts the Connect requests made with a context returned by
+// WithAttemptCounter, so that a tool call can report how often it retried.
+type AttemptCounter struct {
+	requests atomic.Int64
+	retries  atomic.Int64
+}
+
+type attemptCounterKey struct{}
+
+// WithAttemptCounter returns a copy of ctx that counts the requests made with it.
+func WithAttemptCounter(ctx context.Context) (context.Context, *AttemptCounter) {
+	counter := &AttemptCounter{}
+	return context.WithValue(ctx, attemptCounterKey{}, counter), counter
+}
+
+// Requests returns the number of requests sent, including retries.
+func (c *AttemptCounter) Requests() int64 {
+	return c.requests.Load()
+}
+
+// Retries returns the number of requests that were retries of earlier ones.
+func (c *AttemptCounter) Retries() int64 {
+	return c.retries.Load()
+}
+
+func counterFrom(ctx context.Context) *AttemptCounter {
+	if counter, ok := ctx.Value(attemptCounterKey{}).(*AttemptCounter); ok {
+		return counter
+	}
+	// Counting into a throwaway counter keeps retry free of nil checks.
+	return &AttemptCounter{}
+}
@@ -0,0 +1,157 @@
+package connect
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/1password-connect/mcp-server/config"
+	"github.com/1password-connect/mcp-server/connect/connecttest"
+	"github.com/1password-connect/mcp-server/models"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{value: "", ok: false},
+		{value: "0", want: 0, ok: true},
+		{value: "7", want: 7 * time.Second, ok: true},
+		{value: "-1", ok: false},
+		{value: "soon", ok: false},
+		{value: time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat), want: 0, ok: true},
+	}
+	for _, tt := range tests {
+		got, ok := parseRetryAfter(tt.value)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("parseRetryAfter(%q) = %v, %v; want %v, %v", tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+
+	// An HTTP date in the future waits until then, give or take the second
+	// the format rounds to.
+	date := time.Now().Add(30 * time.Second).UTC().Format(http.TimeFormat)
+	got, ok := parseRetryAfter(date)
+	if !ok || got < 28*time.Second || got > 30*time.Second {
+		t.Errorf("parseRetryAfter(%q) = %v, %v; want about 30s", date, got, ok)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	c := &Client{retryPolicy: config.RetryPolicy{MaxAttempts: 5, BaseDelay: 10 * time.Millisecond, MaxDelay: 50 * time.Millisecond}}
+	serverError := &APIError{Kind: KindServer, StatusCode: http.StatusServiceUnavailable}
+	tests := []struct {
+		n    int
+		err  error
+		want time.Duration
+	}{
+		{n: 1, err: serverError, want: 10 * time.Millisecond},
+		{n: 2, err: serverError, want: 20 * time.Millisecond},
+		{n: 3, err: serverError, want: 40 * time.Millisecond},
+		{n: 4, err: serverError, want: 50 * time.Millisecond},
+		{n: 40, err: serverError, want: 50 * time.Millisecond},
+		{n: 1, err: &APIError{Kind: KindRateLimited, StatusCode: http.StatusTooManyRequests, RetryAfter: "2"}, want: 2 * time.Second},
+		{n: 4, err: &APIError{Kind: KindRateLimited, StatusCode: http.StatusTooManyRequests, RetryAfter: "0"}, want: 50 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		if got := c.backoff(tt.n, tt.err); got != tt.want {
+			t.Errorf("backoff(%d, %v) = %v, want %v", tt.n, tt.err, got, tt.want)
+		}
+	}
+
+	c.retryPolicy.Jitter = 0.5
+	for range 100 {
+		if got := c.backoff(2, serverError); got < 10*time.Millisecond || got > 20*time.Millisecond {
+			t.Fatalf("backoff with jitter 0.5 = %v, want between 10ms and 20ms", got)
+		}
+	}
+}
+
+// TestRetry checks which requests are sent again after a 503 from the fake
+// Connect server.
+func TestRetry(t *testing.T) {
+	fake := connecttest.NewServer()
+	defer fake.Close()
+	vault := fake.Store.AddVault("Production", "")
+	item, err := fake.Store.AddItem(vault.Id, models.FullItem{Title: "Database", Category: "LOGIN"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	itemPath := Path("vaults", vault.Id, "items", item.Id)
+	patch := models.Patch{{Op: models.PatchReplace, Path: "/title", Value: "Primary"}}
+
+	tests := []struct {
+		name     string
+		ctx      func(context.Context) context.Context
+		call     func(ctx context.Context, c *Client) error
+		attempts int
+	}{
+		{name: "GET", call: func(ctx context.Context, c *Client) error {
+			_, err := c.Get(ctx, itemPath, nil, nil)
+			return err
+		}, attempts: 2},
+		{name: "PATCH", call: func(ctx context.Context, c *Client) error {
+			_, err := c.Patch(ctx, itemPath, patch, nil)
+			return err
+		}, attempts: 1},
+		{name: "idempotent PATCH", ctx: WithIdempotentPatch, call: func(ctx context.Context, c *Client) error {
+			_, err := c.Patch(ctx, itemPath, patch, nil)
+			return err
+		}, attempts: 2},
+		{name: "POST", call: func(ctx context.Context, c *Client) error {
+			_, err := c.Post(ctx, Path("vaults", vault.Id, "items"), item, nil)
+			return err
+		}, attempts: 1},
+		{name: "PUT without retries", ctx: WithoutRetries, call: func(ctx context.Context, c *Client) error {
+			_, err := c.Put(ctx, itemPath, item, nil)
+			return err
+		}, attempts: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake.ClearFaults()
+			fake.Inject(connecttest.Fault{Path: "/vaults/", Status: http.StatusServiceUnavailable, Times: 1})
+			before := len(fake.Requests())
+			ctx := context.Background()
+			if tt.ctx != nil {
+				ctx = tt.ctx(ctx)
+			}
+			err := tt.call(ctx, NewClient(fake.APIConfig()))
+			if n := len(fake.Requests()) - before; n != tt.attempts {
+				t.Errorf("Connect received %d requests, want %d", n, tt.attempts)
+			}
+			if retried := tt.attempts > 1; retried != (err == nil) {
+				t.Errorf("error = %v", err)
+			}
+		})
+	}
+}
+
+// TestRetryDeadline checks that a retry whose backoff would outlast the
+// caller's deadline is not started.
+func TestRetryDeadline(t *testing.T) {
+	fake := connecttest.NewServer()
+	defer fake.Close()
+	fake.Inject(connecttest.Fault{Path: "/vaults", Status: http.StatusServiceUnavailable})
+	cfg := fake.APIConfig()
+	cfg.Retry = config.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Second, MaxDelay: time.Second}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := NewClient(cfg).Get(ctx, "/vaults", nil, nil)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("error = %v, want the 503", err)
+	}
+	if elapsed := time.Since(start); elapsed > 150*time.Millisecond {
+		t.Errorf("gave up after %v, want at once", elapsed)
+	}
+	if n := len(fake.Requests()); n != 1 {
+		t.Errorf("Connect received %d requests, want 1", n)
+	}
+}
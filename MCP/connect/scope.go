@@ -0,0 +1,93 @@
+package connect
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/1password-connect/mcp-server/models"
+	"github.com/1password-connect/mcp-server/policy"
+)
+
+// checkVault enforces the client's policy on a request to a path under
+// /vaults/{id}. When the policy names vaults, the vault is fetched first to
+// learn its name.
+func (c *Client) checkVault(ctx context.Context, path string) error {
+	if !c.policy.ScopesVaults() {
+		return nil
+	}
+	rest, ok := strings.CutPrefix(path, "/vaults/")
+	if !ok {
+		return nil
+	}
+	escaped, _, _ := strings.Cut(rest, "/")
+	id, err := url.PathUnescape(escaped)
+	if err != nil || id == "" {
+		return nil
+	}
+
+	vault := models.Vault{Id: id}
+	if c.policy.NamesVaults() {
+		vaultPath := "/vaults/" + escaped
+		err := c.retry(ctx, http.MethodGet, vaultPath, func(ctx context.Context) error {
+			_, err := c.do(ctx, http.MethodGet, vaultPath, nil, nil, &vault)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+	tool := policy.ToolFrom(ctx)
+	if !c.policy.AllowsVault(tool, vault) {
+		return &policy.VaultError{Tool: tool, Vault: id}
+	}
+	return nil
+}
+
+// FilterVaults returns the vaults the client's policy lets the calling tool
+// see, as recorded in ctx by policy.WithTool.
+func (c *Client) FilterVaults(ctx context.Context, vaults []models.Vault) []models.Vault {
+	if !c.policy.ScopesVaults() {
+		return vaults
+	}
+	tool := policy.ToolFrom(ctx)
+	visible := make([]models.Vault, 0, len(vaults))
+	for _, v := range vaults {
+		if c.policy.AllowsVault(tool, v) {
+			visible = append(visible, v)
+		}
+	}
+	return visible
+}
+
+// FilterActivity returns the API requests whose vault the client's policy
+// lets the calling tool see, as recorded in ctx by policy.WithTool. Requests
+// that touch no vault are kept. When the policy names vaults, the vaults are
+// listed first to learn their names.
+func (c *Client) FilterActivity(ctx context.Context, requests []models.APIRequest) ([]models.APIRequest, error) {
+	if !c.policy.ScopesVaults() {
+		return requests, nil
+	}
+	names := make(map[string]string)
+	if c.policy.NamesVaults() {
+		var vaults []models.Vault
+		if _, err := c.Get(ctx, Path("vaults"), nil, &vaults); err != nil {
+			return nil, err
+		}
+		for _, v := range vaults {
+			names[v.Id] = v.Name
+		}
+	}
+	tool := policy.ToolFrom(ctx)
+	visible := make([]models.APIRequest, 0, len(requests))
+	for _, r := range requests {
+		vault, _ := r.Resource["vault"].(map[string]interface{})
+		id, _ := vault["id"].(string)
+		if id != "" && !c.policy.AllowsVault(tool, models.Vault{Id: id, Name: names[id]}) {
+			continue
+		}
+		visible = append(visible, r)
+	}
+	return visible, nil
+}
@@ -0,0 +1,80 @@
+package connect
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/1password-connect/mcp-server/connect/connecttest"
+	"github.com/1password-connect/mcp-server/models"
+	"github.com/1password-connect/mcp-server/policy"
+)
+
+// TestCheckVault checks that requests to a vault outside the policy fail
+// before they reach it, looking the vault up only when the policy names
+// vaults.
+func TestCheckVault(t *testing.T) {
+	fake := connecttest.NewServer()
+	defer fake.Close()
+	production := fake.Store.AddVault("Production", "")
+	staging := fake.Store.AddVault("Staging", "")
+
+	tests := []struct {
+		name    string
+		policy  *policy.Policy
+		lookups int // Vault lookups made to learn the vault's name
+	}{
+		{name: "by UUID", policy: &policy.Policy{Vaults: policy.Rule{Allow: []string{production.Id}}}},
+		{name: "by name", policy: &policy.Policy{Vaults: policy.Rule{Allow: []string{"production"}}}, lookups: 1},
+		{name: "for the tool", policy: &policy.Policy{ToolVaults: map[string]policy.Rule{"get_vaults_vaultUuid": {Deny: []string{staging.Id}}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := fake.APIConfig()
+			cfg.Policy = tt.policy
+			c := NewClient(cfg)
+			ctx := policy.WithTool(context.Background(), "get_vaults_vaultUuid")
+
+			before := len(fake.Requests())
+			_, err := c.Get(ctx, Path("vaults", staging.Id), nil, nil)
+			var vaultErr *policy.VaultError
+			if !errors.As(err, &vaultErr) || vaultErr.Vault != staging.Id {
+				t.Errorf("error = %v, want a *policy.VaultError for the staging vault", err)
+			}
+			if n := len(fake.Requests()) - before; n != tt.lookups {
+				t.Errorf("Connect received %d requests, want %d", n, tt.lookups)
+			}
+			if _, err := c.Get(ctx, Path("vaults", production.Id), nil, nil); err != nil {
+				t.Errorf("production vault: %v", err)
+			}
+		})
+	}
+}
+
+func TestFilterActivity(t *testing.T) {
+	fake := connecttest.NewServer()
+	defer fake.Close()
+	production := fake.Store.AddVault("Production", "")
+	staging := fake.Store.AddVault("Staging", "")
+	requests := []models.APIRequest{
+		{Requestid: "production", Resource: map[string]interface{}{"type": "VAULT", "vault": map[string]interface{}{"id": production.Id}}},
+		{Requestid: "staging", Resource: map[string]interface{}{"type": "ITEM", "vault": map[string]interface{}{"id": staging.Id}}},
+		{Requestid: "no vault", Resource: map[string]interface{}{"type": "VAULT"}},
+	}
+
+	for _, entry := range []string{staging.Id, "staging"} {
+		cfg := fake.APIConfig()
+		cfg.Policy = &policy.Policy{Vaults: policy.Rule{Deny: []string{entry}}}
+		visible, err := NewClient(cfg).FilterActivity(context.Background(), requests)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ids []string
+		for _, r := range visible {
+			ids = append(ids, r.Requestid)
+		}
+		if len(ids) != 2 || ids[0] != "production" || ids[1] != "no vault" {
+			t.Errorf("deny %s: visible requests = %v, want production and no vault", entry, ids)
+		}
+	}
+}
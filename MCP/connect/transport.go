@@ -0,0 +1,29 @@
+package connect
+
+import (
+	"net"
+	"net/http"
+	"time"
+)
+
+// sharedTransport is reused by every Client so that connections to a Connect
+// server are pooled across tools and, in HTTP mode, across MCP requests that
+// each build their own configuration.
+var sharedTransport = newTransport()
+
+func newTransport() *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   10 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+	return &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   16,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/1password-connect/mcp-server/connect/connecttest"
+	"github.com/1password-connect/mcp-server/openapi"
+)
+
+// TestContract runs every tool case against a fake Connect server whose
+// traffic is checked against openapi.yaml: each request the tools send must
+// be a documented operation with valid parameters and body, and each answer
+// of the fake must match the documented responses. Every operation in the
+// document must be called by some tool.
+func TestContract(t *testing.T) {
+	spec, err := openapi.Load("../openapi.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	validator, err := spec.Validator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ops, err := spec.Operations()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, newClient := range transports {
+		t.Run(name, func(t *testing.T) {
+			called := &calledOperations{}
+			ran := 0
+			for _, tc := range toolCases {
+				t.Run(tc.tool, func(t *testing.T) {
+					ran++
+					fake := connecttest.NewUnstartedServer()
+					fake.Config.Handler = checkContract(t, validator, called, fake.Config.Handler)
+					fake.Start()
+					f := seedFixture(t, fake)
+					var args map[string]any
+					if tc.args != nil {
+						args = tc.args(f)
+					}
+					tc.check(t, f, callTool(t, newClient(t, f.connect.APIConfig()), tc.tool, args))
+				})
+			}
+			// Only a full run shows which operations no tool calls.
+			if ran < len(toolCases) {
+				return
+			}
+			for _, op := range ops {
+				if !called.has(op.ID) {
+					t.Errorf("no tool calls %s (%s %s)", op.ID, op.Method, op.Path)
+				}
+			}
+		})
+	}
+}
+
+// calledOperations collects the IDs of the operations requested.
+type calledOperations struct {
+	mu  sync.Mutex
+	ids []string
+}
+
+func (c *calledOperations) add(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !slices.Contains(c.ids, id) {
+		c.ids = append(c.ids, id)
+	}
+}
+
+func (c *calledOperations) has(id string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Contains(c.ids, id)
+}
+
+// checkContract wraps the fake's handler, failing t for every request or
+// response that departs from the document.
+func checkContract(t *testing.T, validator *openapi.Validator, called *calledOperations, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("reading request body: %v", err)
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		path := strings.TrimPrefix(r.URL.Path, "/v1")
+		op, err := validator.Request(r.Method, path, r.URL.Query(), r.Header, body)
+		if err != nil {
+			t.Errorf("request drifts from openapi.yaml: %v\n%s", err, body)
+			next.ServeHTTP(w, r)
+			return
+		}
+		called.add(op.ID)
+
+		rec := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		if err := validator.Response(op, rec.status, w.Header(), rec.body.Bytes()); err != nil {
+			t.Errorf("response drifts from openapi.yaml: %v\n%s", err, rec.body.Bytes())
+		}
+	})
+}
+
+// responseRecorder keeps a copy of the response it writes.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
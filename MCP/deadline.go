@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/1password-connect/mcp-server/models"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// timeoutMetaKey lets a client ask for a tighter or looser deadline on a
+	// single tool call, in milliseconds.
+	timeoutMetaKey = "timeoutMs"
+
+	defaultToolTimeout = 60 * time.Second
+	maxToolTimeout     = 5 * time.Minute
+)
+
+// withDeadline bounds a tool handler by the tool's own timeout, or by the
+// timeout the client asked for in the request's _meta, capped at
+// maxToolTimeout.
+func withDeadline(tool models.Tool) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		timeout := tool.Timeout
+		if timeout <= 0 {
+			timeout = defaultToolTimeout
+		}
+		if ms, ok := metaField(request, timeoutMetaKey).(float64); ok && ms > 0 {
+			timeout = time.Duration(ms) * time.Millisecond
+		}
+		timeout = min(timeout, maxToolTimeout)
+
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		return tool.Handler(ctx, request)
+	}
+}
+
+func metaField(request mcp.CallToolRequest, key string) any {
+	if request.Params.Meta == nil {
+		return nil
+	}
+	return request.Params.Meta.AdditionalFields[key]
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/1password-connect/mcp-server/models"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestWithDeadline(t *testing.T) {
+	tests := []struct {
+		name      string
+		timeout   time.Duration // The tool's own timeout
+		timeoutMs any           // The _meta value, if set
+		want      time.Duration
+	}{
+		{name: "default", want: defaultToolTimeout},
+		{name: "tool timeout", timeout: 2 * time.Minute, want: 2 * time.Minute},
+		{name: "requested", timeout: 2 * time.Minute, timeoutMs: float64(1500), want: 1500 * time.Millisecond},
+		{name: "clamped", timeoutMs: float64(24 * time.Hour / time.Millisecond), want: maxToolTimeout},
+		{name: "tool timeout clamped", timeout: time.Hour, want: maxToolTimeout},
+		{name: "not positive", timeoutMs: float64(-1), want: defaultToolTimeout},
+		{name: "not a number", timeoutMs: "50", want: defaultToolTimeout},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got time.Duration
+			handler := withDeadline(models.Tool{
+				Timeout: tt.timeout,
+				Handler: func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+					deadline, ok := ctx.Deadline()
+					if !ok {
+						t.Fatal("handler has no deadline")
+					}
+					got = time.Until(deadline)
+					return mcp.NewToolResultText("ok"), nil
+				},
+			})
+			request := mcp.CallToolRequest{}
+			if tt.timeoutMs != nil {
+				request.Params.Meta = &mcp.Meta{AdditionalFields: map[string]any{timeoutMetaKey: tt.timeoutMs}}
+			}
+			if _, err := handler(context.Background(), request); err != nil {
+				t.Fatal(err)
+			}
+			if got > tt.want || got < tt.want-time.Second {
+				t.Errorf("deadline in %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,5 @@
+package main
+
+// Files ending in _gen.go are generated from the Connect API's OpenAPI
+// document; run go generate after changing it.
+//go:generate go run ./cmd/openapigen -spec ../openapi.yaml
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"flag"
 	"log"
 	"net"
 	"net/http"
@@ -12,13 +13,24 @@ import (
 
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/1password-connect/mcp-server/config"
+	"github.com/1password-connect/mcp-server/resources"
+	"github.com/1password-connect/mcp-server/session"
 )
 
 func main() {
+	readOnly := flag.Bool("read-only", false, "Disable every tool that modifies vaults (also READ_ONLY=true)")
+	flag.Parse()
+
 	cfg, err := config.LoadAPIConfig()
 	if err != nil {
 		log.Fatalf("Failed to load config: %v", err)
 	}
+	if *readOnly {
+		cfg.ReadOnly = true
+	}
+	if cfg.ReadOnly {
+		log.Println("Read-only mode: tools that modify vaults are disabled")
+	}
 
 	// Check transport environment variable (both uppercase and lowercase)
 	transport := os.Getenv("TRANSPORT")
@@ -28,50 +40,57 @@ func main() {
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
-	// HTTP/HTTPS Mode - if transport is "http", "HTTP", "https", or "HTTPS"
-	if transport == "http" || transport == "HTTP" || transport == "https" || transport == "HTTPS" {
+	// baseCtx parents every tool call; cancelling it aborts in-flight Connect
+	// requests once shutdown can no longer wait for them.
+	baseCtx, cancelBase := context.WithCancel(context.Background())
+	defer cancelBase()
+
+	// HTTP/HTTPS/SSE Mode - if transport is "http", "https" or "sse" in either case
+	if config.IsNetworkTransport(transport) {
 		port := cfg.Port
 		if port == "" {
-			log.Fatalf("PORT environment variable is required for HTTP/HTTPS mode. Please set PORT environment variable.")
+			log.Fatalf("PORT environment variable is required for HTTP/HTTPS/SSE mode. Please set PORT environment variable.")
 		}
 
-		// Determine if HTTPS mode and normalize transport
-		isHTTPS := transport == "https" || transport == "HTTPS"
-		if isHTTPS {
+		certFile := os.Getenv("CERT_FILE")
+		keyFile := os.Getenv("KEY_FILE")
+
+		// Determine if HTTPS or SSE mode and normalize transport. SSE shares
+		// the HTTPS options and serves TLS whenever a certificate is configured.
+		isSSE := transport == "sse" || transport == "SSE"
+		isHTTPS := transport == "https" || transport == "HTTPS" || (isSSE && certFile != "" && keyFile != "")
+		switch {
+		case isSSE:
+			transport = "SSE"
+		case isHTTPS:
 			transport = "HTTPS"
-		} else {
+		default:
 			transport = "HTTP"
 		}
-		
+
 		log.Printf("Running in %s mode on port %s", transport, port)
 
+		hooks := &server.Hooks{}
 		mux := http.NewServeMux()
-		mux.HandleFunc("/mcp", func(w http.ResponseWriter, r *http.Request) {
-			// Read headers for dynamic config
-			apiCfg := &config.APIConfig{
-				BaseURL:     r.Header.Get("API_BASE_URL"),
-				BearerToken: r.Header.Get("BEARER_TOKEN"),
-				APIKey:      r.Header.Get("API_KEY"),
-				BasicAuth:   r.Header.Get("BASIC_AUTH"),
-			}
-
-			if apiCfg.BaseURL == "" {
-				http.Error(w, "Missing API_BASE_URL header", http.StatusBadRequest)
-				return
-			}
-
-			log.Printf("Incoming HTTP request - BaseURL: %s", apiCfg.BaseURL)
-
-			// Create MCP server for this request
-			mcpSrv := createMCPServer(apiCfg, transport)
-			handler := server.NewStreamableHTTPServer(mcpSrv, server.WithHTTPContextFunc(
-				func(ctx context.Context, req *http.Request) context.Context {
-					return context.WithValue(ctx, "apiConfig", apiCfg)
-				},
-			))
-
-			handler.ServeHTTP(w, r)
-		})
+		if isSSE {
+			// Legacy HTTP+SSE transport: credentials come with the GET /sse
+			// request and are bound to the session for its lifetime.
+			credentials := newSSECredentials()
+			credentials.register(hooks)
+			mcpSrv, registry := createMCPServer(cfg, transport, hooks)
+			go registry.Watch(baseCtx)
+			sseHandler, messageHandler := credentials.handlers(mcpSrv, cfg)
+			mux.Handle("/sse", sseHandler)
+			mux.Handle("/message", messageHandler)
+		} else {
+			// One long-lived MCP server serves every session; each session's
+			// Connect credentials are bound when it is initialized.
+			mcpSrv, registry := createMCPServer(cfg, transport, hooks)
+			go registry.Watch(baseCtx)
+			sessions := session.NewManager(cfg.SessionTTL)
+			go sessions.Run(baseCtx, time.Minute)
+			mux.Handle("/mcp", streamableHandler(mcpSrv, cfg, sessions))
+		}
 
 		mux.HandleFunc("/", func(w http.ResponseWriter, _ *http.Request) {
 			w.Header().Set("Content-Type", "application/json")
@@ -79,14 +98,15 @@ func main() {
 		})
 
 		addr := net.JoinHostPort("0.0.0.0", port)
-		httpServer := &http.Server{Addr: addr, Handler: mux}
+		httpServer := &http.Server{
+			Addr:        addr,
+			Handler:     mux,
+			BaseContext: func(net.Listener) context.Context { return baseCtx },
+		}
 
 		go func() {
 			// Check if HTTPS mode
 			if isHTTPS {
-				certFile := os.Getenv("CERT_FILE")
-				keyFile := os.Getenv("KEY_FILE")
-				
 				if certFile == "" || keyFile == "" {
 					log.Fatalf("CERT_FILE and KEY_FILE environment variables are required for HTTPS mode")
 				}
@@ -108,6 +128,8 @@ func main() {
 
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
+		// Give in-flight tool calls the grace period, then cancel their Connect requests.
+		context.AfterFunc(ctx, cancelBase)
 		if err := httpServer.Shutdown(ctx); err != nil {
 			log.Printf("Shutdown error: %v", err)
 		} else {
@@ -118,28 +140,55 @@ func main() {
 
 	// STDIO Mode - default when no transport or transport is "stdio"
 	log.Println("Running in STDIO mode")
-	mcp := createMCPServer(cfg, "STDIO")
+	authType, err := cfg.ResolveAuthType()
+	if err != nil {
+		log.Fatalf("Invalid authentication configuration: %v", err)
+	}
+	log.Printf("Using %s authentication", authType)
+	mcp, registry := createMCPServer(cfg, "STDIO", &server.Hooks{})
+	// STDIO runs with fixed credentials, so its vaults can be listed up front.
+	if _, err := registry.SyncVaults(baseCtx); err != nil {
+		log.Printf("Failed to list vault resources: %v", err)
+	}
+	go registry.Watch(baseCtx)
 	go func() {
-		if err := server.ServeStdio(mcp); err != nil {
+		if err := server.NewStdioServer(mcp).Listen(baseCtx, os.Stdin, os.Stdout); err != nil && err != context.Canceled {
 			log.Fatalf("STDIO error: %v", err)
 		}
 	}()
 	<-sigChan
 	log.Println("Received shutdown signal. Exiting STDIO mode.")
+	cancelBase()
 }
 
-func createMCPServer(cfg *config.APIConfig, mode string) *server.MCPServer {
+// createMCPServer builds the MCP server for a transport, along with the
+// registry of its vault and item resources. Transports that need to observe
+// sessions pass hooks they have already registered on.
+func createMCPServer(cfg *config.APIConfig, mode string, hooks *server.Hooks) (*server.MCPServer, *resources.Registry) {
+	// The server info tells clients whether writes are available.
+	title, description := "1Password Connect", "Vaults, items and files of a 1Password Connect server"
+	if cfg.ReadOnly {
+		title += " (read-only)"
+		description = "Read-only access to the vaults, items and files of a 1Password Connect server; tools that modify vaults are disabled"
+	}
 	mcp := server.NewMCPServer("1Password Connect", "1.5.7",
+		server.WithTitle(title),
+		server.WithDescription(description),
 		server.WithToolCapabilities(true),
+		server.WithResourceCapabilities(true, true),
 		server.WithRecovery(),
+		server.WithHooks(hooks),
+		server.WithToolHandlerMiddleware(reportAttempts),
+		server.WithToolHandlerMiddleware(scopeTool),
+		server.WithToolFilter(filterTools),
 	)
 
 	tools := GetAll(cfg)
 	log.Printf("Loaded %d tools for %s mode", len(tools), mode)
 
 	for _, tool := range tools {
-		mcp.AddTool(tool.Definition, tool.Handler)
+		mcp.AddTool(tool.Definition, withDeadline(tool))
 	}
 
-	return mcp
-}
\ No newline at end of file
+	return mcp, resources.Register(mcp, cfg, hooks)
+}
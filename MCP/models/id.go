@@ -0,0 +1,18 @@
+package models
+
+// uuidLength is the length of the IDs Connect assigns to vaults and items.
+const uuidLength = 26
+
+// IsUUID reports whether s has the shape of a Connect ID: 26 lowercase
+// letters or digits. Anything else can only be a name.
+func IsUUID(s string) bool {
+	if len(s) != uuidLength {
+		return false
+	}
+	for _, r := range s {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,21 @@
+package models
+
+import "testing"
+
+func TestIsUUID(t *testing.T) {
+	tests := map[string]bool{
+		"abcdefghijklmnopqrstuvwxyz":  true,
+		"0123456789abcdefghijklmnop":  true,
+		"ABCDEFGHIJKLMNOPQRSTUVWXYZ":  false,
+		"abcdefghijklmnopqrstuvwxy":   false,
+		"abcdefghijklmnopqrstuvwxyz0": false,
+		"abcdefghijklm-opqrstuvwxyz":  false,
+		"Production":                  false,
+		"":                            false,
+	}
+	for s, want := range tests {
+		if got := IsUUID(s); got != want {
+			t.Errorf("IsUUID(%q) = %v, want %v", s, got, want)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+// Code generated by openapigen from openapi.yaml. DO NOT EDIT.
+
+package models
+
+// APIRequest represents the APIRequest schema from the OpenAPI specification
+type APIRequest struct {
+	Action    string                 `json:"action,omitempty"`
+	Actor     map[string]interface{} `json:"actor,omitempty"`
+	Requestid string                 `json:"requestId,omitempty"` // The unique id used to identify a single request.
+	Resource  map[string]interface{} `json:"resource,omitempty"`
+	Result    string                 `json:"result,omitempty"`
+	Timestamp string                 `json:"timestamp,omitempty"` // The time at which the request was processed by the server.
+}
+
+// ErrorResponse represents the ErrorResponse schema from the OpenAPI specification
+type ErrorResponse struct {
+	Message string `json:"message,omitempty"` // A message detailing the error
+	Status  int    `json:"status,omitempty"`  // HTTP Status Code
+}
+
+// Field represents the Field schema from the OpenAPI specification
+type Field struct {
+	Entropy   float64                `json:"entropy,omitempty"`  // For fields with a purpose of `PASSWORD` this is the entropy of the value
+	Generate  bool                   `json:"generate,omitempty"` // If value is not present then a new value should be generated for this field
+	Id        string                 `json:"id"`
+	Label     string                 `json:"label,omitempty"`
+	Purpose   string                 `json:"purpose,omitempty"` // Some item types, Login and Password, have fields used for autofill. This property indicates that purpose and is required for some item types.
+	Recipe    *GeneratorRecipe       `json:"recipe,omitempty"`  // The recipe is used in conjunction with the "generate" property to set the character set used to generate a new secure value
+	Section   map[string]interface{} `json:"section,omitempty"`
+	Totp      string                 `json:"totp,omitempty"` // Current one-time password of a TOTP field, computed by the Connect server
+	TypeField string                 `json:"type"`
+	Value     string                 `json:"value,omitempty"`
+}
+
+// File represents the File schema from the OpenAPI specification
+type File struct {
+	Content      string                 `json:"content,omitempty"`      // Base64-encoded contents of the file. Only set if size <= OP_MAX_INLINE_FILE_SIZE_KB kb and `inline_files` is set to `true`.
+	Content_path string                 `json:"content_path,omitempty"` // Path of the Connect API that can be used to download the contents of this file.
+	Id           string                 `json:"id,omitempty"`           // ID of the file
+	Name         string                 `json:"name,omitempty"`         // Name of the file
+	Section      map[string]interface{} `json:"section,omitempty"`      // For files that are in a section, this field describes the section.
+	Size         int                    `json:"size,omitempty"`         // Size in bytes of the file
+}
+
+// FullItem represents the FullItem schema from the OpenAPI specification
+type FullItem struct {
+	Category     string                   `json:"category"`
+	Createdat    string                   `json:"createdAt,omitempty"`
+	Favorite     bool                     `json:"favorite,omitempty"`
+	Fields       []Field                  `json:"fields,omitempty"`
+	Files        []File                   `json:"files,omitempty"`
+	Id           string                   `json:"id,omitempty"`
+	Lasteditedby string                   `json:"lastEditedBy,omitempty"`
+	Sections     []map[string]interface{} `json:"sections,omitempty"`
+	State        string                   `json:"state,omitempty"`
+	Tags         []string                 `json:"tags,omitempty"`
+	Title        string                   `json:"title,omitempty"`
+	Updatedat    string                   `json:"updatedAt,omitempty"`
+	Urls         []map[string]interface{} `json:"urls,omitempty"`
+	Vault        map[string]interface{}   `json:"vault"`
+	Version      int                      `json:"version,omitempty"`
+}
+
+// GeneratorRecipe represents the GeneratorRecipe schema from the OpenAPI specification
+type GeneratorRecipe struct {
+	Charactersets     []string `json:"characterSets,omitempty"`
+	Excludecharacters string   `json:"excludeCharacters,omitempty"` // List of all characters that should be excluded from generated passwords.
+	Length            int      `json:"length,omitempty"`            // Length of the generated value
+}
+
+// Item represents the Item schema from the OpenAPI specification
+type Item struct {
+	Category     string                   `json:"category"`
+	Createdat    string                   `json:"createdAt,omitempty"`
+	Favorite     bool                     `json:"favorite,omitempty"`
+	Id           string                   `json:"id,omitempty"`
+	Lasteditedby string                   `json:"lastEditedBy,omitempty"`
+	State        string                   `json:"state,omitempty"`
+	Tags         []string                 `json:"tags,omitempty"`
+	Title        string                   `json:"title,omitempty"`
+	Updatedat    string                   `json:"updatedAt,omitempty"`
+	Urls         []map[string]interface{} `json:"urls,omitempty"`
+	Vault        map[string]interface{}   `json:"vault"`
+	Version      int                      `json:"version,omitempty"`
+}
+
+// Patch represents the Patch schema from the OpenAPI specification
+type Patch []PatchOperation
+
+// PatchOperation is an element of Patch
+type PatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`            // An RFC6901 JSON Pointer pointing to the Item document, an Item Attribute, and Item Field by Field ID, or an Item Field Attribute
+	Value interface{} `json:"value,omitempty"` // The value to add or replace with, which may be any JSON value
+}
+
+// ServiceDependency represents the ServiceDependency schema from the OpenAPI specification
+type ServiceDependency struct {
+	Message string `json:"message,omitempty"` // Human-readable message for explaining the current state.
+	Service string `json:"service,omitempty"`
+	Status  string `json:"status,omitempty"`
+}
+
+// Vault represents the Vault schema from the OpenAPI specification
+type Vault struct {
+	Attributeversion int    `json:"attributeVersion,omitempty"` // The vault version
+	Contentversion   int    `json:"contentVersion,omitempty"`   // The version of the vault contents
+	Createdat        string `json:"createdAt,omitempty"`
+	Description      string `json:"description,omitempty"`
+	Id               string `json:"id,omitempty"`
+	Items            int    `json:"items,omitempty"` // Number of active items in the vault
+	Name             string `json:"name,omitempty"`
+	TypeField        string `json:"type,omitempty"`
+	Updatedat        string `json:"updatedAt,omitempty"`
+}
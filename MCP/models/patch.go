@@ -0,0 +1,179 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// The operations of a Patch that Connect supports.
+const (
+	PatchAdd     = "add"
+	PatchRemove  = "remove"
+	PatchReplace = "replace"
+)
+
+// patchTarget describes an item path that patches may change: the
+// operations allowed on it, and the type its value must decode into.
+type patchTarget struct {
+	ops   []string
+	value func() any
+}
+
+func newValue[T any]() any { return new(T) }
+
+// patchTargets are keyed by path, with {id} standing for a field ID.
+var patchTargets = map[string]patchTarget{
+	"/title":             {[]string{PatchAdd, PatchReplace}, newValue[string]},
+	"/tags":              {[]string{PatchAdd, PatchReplace, PatchRemove}, newValue[tagsValue]},
+	"/sections":          {[]string{PatchAdd, PatchReplace, PatchRemove}, newValue[[]map[string]any]},
+	"/fields":            {[]string{PatchAdd}, newValue[Field]},
+	"/fields/{id}":       {[]string{PatchReplace, PatchRemove}, newValue[Field]},
+	"/fields/{id}/value": {[]string{PatchAdd, PatchReplace, PatchRemove}, newValue[string]},
+}
+
+// tagsValue is a list of tags, or a single tag to add to them.
+type tagsValue []string
+
+func (t *tagsValue) UnmarshalJSON(data []byte) error {
+	var tag string
+	if err := json.Unmarshal(data, &tag); err == nil {
+		*t = tagsValue{tag}
+		return nil
+	}
+	return json.Unmarshal(data, (*[]string)(t))
+}
+
+// Validate checks that every operation of p is one Connect applies to an
+// item.
+func (p Patch) Validate() error {
+	for i, op := range p {
+		if err := op.Validate(); err != nil {
+			return fmt.Errorf("patch operation %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// Validate checks that op changes a path of an item Connect accepts, with an
+// operation allowed there and a value of the right type. The paths are
+// /title, /tags, /sections, /fields to add a field, and /fields/{id} and
+// /fields/{id}/value to change a field given by its ID.
+func (op PatchOperation) Validate() error {
+	if !slices.Contains([]string{PatchAdd, PatchRemove, PatchReplace}, op.Op) {
+		return fmt.Errorf("unsupported op %q: use add, remove or replace", op.Op)
+	}
+	target, ok := patchTargets[patchPattern(op.Path)]
+	if !ok {
+		return fmt.Errorf("unsupported path %q: use /title, /tags, /sections, /fields, /fields/{id} or /fields/{id}/value", op.Path)
+	}
+	if !slices.Contains(target.ops, op.Op) {
+		return fmt.Errorf("cannot %s %s", op.Op, op.Path)
+	}
+	if op.Op == PatchRemove {
+		if op.Value != nil {
+			return fmt.Errorf("remove %s takes no value", op.Path)
+		}
+		return nil
+	}
+	if op.Value == nil {
+		return fmt.Errorf("%s %s needs a value", op.Op, op.Path)
+	}
+	data, err := json.Marshal(op.Value)
+	if err != nil {
+		return fmt.Errorf("invalid value for %s: %w", op.Path, err)
+	}
+	if err := json.Unmarshal(data, target.value()); err != nil {
+		return fmt.Errorf("invalid value for %s: %w", op.Path, err)
+	}
+	return nil
+}
+
+// patchPattern replaces the field ID in a path with {id}.
+func patchPattern(path string) string {
+	rest, ok := strings.CutPrefix(path, "/fields/")
+	if !ok {
+		return path
+	}
+	id, attr, nested := strings.Cut(rest, "/")
+	if id == "" {
+		return path
+	}
+	if !nested {
+		return "/fields/{id}"
+	}
+	return "/fields/{id}/" + attr
+}
+
+// ItemPatch returns the smallest patch that turns current into desired, a
+// partial item: its title, tags and sections are set when they are not
+// empty, and its fields are matched to those of current by ID. Of a matched
+// field only the attributes desired sets change, and a field with a new ID
+// is added. Attributes and fields desired leaves out stay as they are.
+func ItemPatch(current, desired FullItem) Patch {
+	var patch Patch
+	if desired.Title != "" && desired.Title != current.Title {
+		patch = append(patch, setOperation("/title", current.Title != "", desired.Title))
+	}
+	if desired.Tags != nil && !slices.Equal(desired.Tags, current.Tags) {
+		patch = append(patch, setOperation("/tags", current.Tags != nil, desired.Tags))
+	}
+	if desired.Sections != nil && !reflect.DeepEqual(desired.Sections, current.Sections) {
+		patch = append(patch, setOperation("/sections", current.Sections != nil, desired.Sections))
+	}
+	for _, want := range desired.Fields {
+		i := slices.IndexFunc(current.Fields, func(f Field) bool { return f.Id == want.Id })
+		if i < 0 {
+			patch = append(patch, PatchOperation{Op: PatchAdd, Path: "/fields", Value: want})
+			continue
+		}
+		patch = append(patch, FieldPatch(current.Fields[i], want)...)
+	}
+	return patch
+}
+
+// FieldPatch returns the smallest patch that gives field the attributes
+// desired sets. A change of the value alone patches /fields/{id}/value;
+// other changes, and asking for a generated value, replace the field.
+func FieldPatch(field, desired Field) Patch {
+	merged := field
+	merged.Entropy, merged.Totp = 0, ""
+	if desired.Label != "" {
+		merged.Label = desired.Label
+	}
+	if desired.Purpose != "" {
+		merged.Purpose = desired.Purpose
+	}
+	if desired.TypeField != "" {
+		merged.TypeField = desired.TypeField
+	}
+	if desired.Section != nil {
+		merged.Section = desired.Section
+	}
+	if desired.Generate {
+		merged.Generate, merged.Recipe, merged.Value = true, desired.Recipe, ""
+	} else if desired.Value != "" {
+		merged.Value = desired.Value
+	}
+
+	path := "/fields/" + field.Id
+	valueChanged := field
+	valueChanged.Entropy, valueChanged.Totp, valueChanged.Value = 0, "", merged.Value
+	if desired.Generate || !reflect.DeepEqual(valueChanged, merged) {
+		return Patch{{Op: PatchReplace, Path: path, Value: merged}}
+	}
+	if merged.Value == field.Value {
+		return nil
+	}
+	return Patch{setOperation(path+"/value", field.Value != "", merged.Value)}
+}
+
+// setOperation sets path to value, replacing what exists there.
+func setOperation(path string, exists bool, value any) PatchOperation {
+	if exists {
+		return PatchOperation{Op: PatchReplace, Path: path, Value: value}
+	}
+	return PatchOperation{Op: PatchAdd, Path: path, Value: value}
+}
@@ -0,0 +1,103 @@
+package models
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPatchValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		op   PatchOperation
+		want string // Substring of the error; empty when the operation is valid
+	}{
+		{name: "title", op: PatchOperation{Op: "replace", Path: "/title", Value: "Prod"}},
+		{name: "append tag", op: PatchOperation{Op: "add", Path: "/tags", Value: "prod"}},
+		{name: "set tags", op: PatchOperation{Op: "replace", Path: "/tags", Value: []any{"db", "prod"}}},
+		{name: "sections", op: PatchOperation{Op: "add", Path: "/sections", Value: []any{map[string]any{"id": "admin"}}}},
+		{name: "add field", op: PatchOperation{Op: "add", Path: "/fields", Value: map[string]any{"id": "pin", "type": "CONCEALED"}}},
+		{name: "remove field", op: PatchOperation{Op: "remove", Path: "/fields/pin"}},
+		{name: "field value", op: PatchOperation{Op: "replace", Path: "/fields/password/value", Value: "hunter2"}},
+
+		{name: "move", op: PatchOperation{Op: "move", Path: "/title"}, want: "unsupported op"},
+		{name: "read-only attribute", op: PatchOperation{Op: "replace", Path: "/id", Value: "x"}, want: "unsupported path"},
+		{name: "whole item", op: PatchOperation{Op: "replace", Path: "/", Value: map[string]any{}}, want: "unsupported path"},
+		{name: "field label", op: PatchOperation{Op: "replace", Path: "/fields/pin/label", Value: "PIN"}, want: "unsupported path"},
+		{name: "remove title", op: PatchOperation{Op: "remove", Path: "/title"}, want: "cannot remove"},
+		{name: "missing value", op: PatchOperation{Op: "replace", Path: "/title"}, want: "needs a value"},
+		{name: "value on remove", op: PatchOperation{Op: "remove", Path: "/tags", Value: "db"}, want: "takes no value"},
+		{name: "title of the wrong type", op: PatchOperation{Op: "replace", Path: "/title", Value: 3}, want: "invalid value"},
+		{name: "field value of the wrong type", op: PatchOperation{Op: "add", Path: "/fields/pin/value", Value: []any{"1"}}, want: "invalid value"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Patch{tt.op}.Validate()
+			switch {
+			case tt.want == "" && err != nil:
+				t.Errorf("valid operation rejected: %v", err)
+			case tt.want != "" && err == nil:
+				t.Errorf("invalid operation accepted, want an error about %q", tt.want)
+			case tt.want != "" && !strings.Contains(err.Error(), tt.want):
+				t.Errorf("error = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestItemPatch(t *testing.T) {
+	current := FullItem{
+		Title: "Database",
+		Tags:  []string{"db"},
+		Fields: []Field{
+			{Id: "username", TypeField: "STRING", Purpose: "USERNAME", Value: "admin"},
+			{Id: "password", TypeField: "CONCEALED", Purpose: "PASSWORD", Value: "hunter2", Entropy: 38},
+			{Id: "pin", TypeField: "CONCEALED", Label: "PIN"},
+		},
+	}
+	recipe := &GeneratorRecipe{Length: 20, Charactersets: []string{"LETTERS"}}
+
+	tests := []struct {
+		name    string
+		desired FullItem
+		want    string // JSON of the patch
+	}{
+		{name: "nothing", desired: FullItem{}, want: `null`},
+		{name: "same values", desired: FullItem{Title: "Database", Tags: []string{"db"}, Fields: []Field{{Id: "username", Value: "admin"}}}, want: `null`},
+		{name: "title and tags", desired: FullItem{Title: "Primary", Tags: []string{"db", "prod"}},
+			want: `[{"op":"replace","path":"/title","value":"Primary"},{"op":"replace","path":"/tags","value":["db","prod"]}]`},
+		{name: "value", desired: FullItem{Fields: []Field{{Id: "password", Value: "correct horse"}}},
+			want: `[{"op":"replace","path":"/fields/password/value","value":"correct horse"}]`},
+		{name: "first value", desired: FullItem{Fields: []Field{{Id: "pin", Value: "1234"}}},
+			want: `[{"op":"add","path":"/fields/pin/value","value":"1234"}]`},
+		{name: "label", desired: FullItem{Fields: []Field{{Id: "pin", Label: "Admin PIN"}}},
+			want: `[{"op":"replace","path":"/fields/pin","value":{"id":"pin","label":"Admin PIN","type":"CONCEALED"}}]`},
+		{name: "generate", desired: FullItem{Fields: []Field{{Id: "password", Generate: true, Recipe: recipe}}},
+			want: `[{"op":"replace","path":"/fields/password","value":{"generate":true,"id":"password","purpose":"PASSWORD","recipe":{"characterSets":["LETTERS"],"length":20},"type":"CONCEALED"}}]`},
+		{name: "new field", desired: FullItem{Fields: []Field{{Id: "host", TypeField: "STRING", Value: "db.internal"}}},
+			want: `[{"op":"add","path":"/fields","value":{"id":"host","type":"STRING","value":"db.internal"}}]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patch := ItemPatch(current, tt.desired)
+			if err := patch.Validate(); err != nil {
+				t.Errorf("invalid patch: %v", err)
+			}
+			got, err := json.Marshal(patch)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var gotJSON, wantJSON any
+			if err := json.Unmarshal(got, &gotJSON); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal([]byte(tt.want), &wantJSON); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(gotJSON, wantJSON) {
+				t.Errorf("patch = %s\nwant    %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,339 @@
+// Package openapi reads the Connect API's OpenAPI document, turns its
+// component schemas into self-contained JSON schemas, and checks requests and
+// responses against its operations.
+package openapi
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Direction selects how readOnly properties are treated when resolving a
+// schema.
+type Direction int
+
+const (
+	// Response keeps every property.
+	Response Direction = iota
+	// Request drops readOnly properties, which Connect sets itself.
+	Request
+)
+
+const schemaRef = "#/components/schemas/"
+
+// Spec is a parsed OpenAPI document.
+type Spec struct {
+	doc map[string]any
+}
+
+// Load reads an OpenAPI document in YAML or JSON.
+func Load(path string) (*Spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read OpenAPI document: %w", err)
+	}
+	var doc map[string]any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI document %s: %w", path, err)
+	}
+	return &Spec{doc: doc}, nil
+}
+
+// Operation is an operation in the document's paths.
+type Operation struct {
+	ID          string
+	Method      string // Upper case, as in net/http
+	Path        string
+	Tag         string // The first tag, which groups the operation
+	Summary     string
+	Description string
+	Parameters  []Parameter
+	// Body is the application/json request body schema as written in the
+	// document, or nil when the operation takes no body.
+	Body map[string]any
+	// Responses holds the documented responses by status code, or default.
+	Responses map[string]Content
+	// Secured operations must be called with the Connect token.
+	Secured bool
+}
+
+// Content maps the media types of a response to their schemas as written in
+// the document. Media types documented without a schema map to nil.
+type Content map[string]map[string]any
+
+// Parameter is a path or query parameter of an operation.
+type Parameter struct {
+	Name        string
+	In          string
+	Description string
+	Required    bool
+	Schema      map[string]any
+}
+
+var methods = []string{http.MethodGet, http.MethodPut, http.MethodPost, http.MethodDelete, http.MethodOptions, http.MethodHead, http.MethodPatch, http.MethodTrace}
+
+// Operations returns every operation, ordered by path and then method.
+// Parameters declared on a path apply to each of its operations.
+func (s *Spec) Operations() ([]Operation, error) {
+	paths, _ := s.doc["paths"].(map[string]any)
+	var ops []Operation
+	for path, item := range paths {
+		item, ok := item.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("path %s must be an object", path)
+		}
+		shared, err := parameters(item["parameters"])
+		if err != nil {
+			return nil, fmt.Errorf("path %s: %w", path, err)
+		}
+		for _, method := range methods {
+			raw, ok := item[strings.ToLower(method)].(map[string]any)
+			if !ok {
+				continue
+			}
+			op := Operation{Method: method, Path: path}
+			op.ID, _ = raw["operationId"].(string)
+			op.Summary, _ = raw["summary"].(string)
+			op.Description, _ = raw["description"].(string)
+			if tags, ok := raw["tags"].([]any); ok && len(tags) > 0 {
+				op.Tag, _ = tags[0].(string)
+			}
+			if op.ID == "" {
+				return nil, fmt.Errorf("%s %s has no operationId", method, path)
+			}
+			own, err := parameters(raw["parameters"])
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", op.ID, err)
+			}
+			op.Parameters = append(slices.Clone(shared), own...)
+			if body, ok := raw["requestBody"].(map[string]any); ok {
+				content, _ := body["content"].(map[string]any)
+				media, _ := content["application/json"].(map[string]any)
+				op.Body, _ = media["schema"].(map[string]any)
+				if op.Body == nil {
+					return nil, fmt.Errorf("%s: only application/json request bodies are supported", op.ID)
+				}
+			}
+			op.Responses, err = responses(raw["responses"])
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", op.ID, err)
+			}
+			security, _ := raw["security"].([]any)
+			op.Secured = len(security) > 0
+			ops = append(ops, op)
+		}
+	}
+	sort.Slice(ops, func(i, j int) bool {
+		if ops[i].Path != ops[j].Path {
+			return ops[i].Path < ops[j].Path
+		}
+		return slices.Index(methods, ops[i].Method) < slices.Index(methods, ops[j].Method)
+	})
+	return ops, nil
+}
+
+func parameters(raw any) ([]Parameter, error) {
+	list, _ := raw.([]any)
+	params := make([]Parameter, 0, len(list))
+	for _, entry := range list {
+		entry, ok := entry.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("parameters must be objects")
+		}
+		var p Parameter
+		p.Name, _ = entry["name"].(string)
+		p.In, _ = entry["in"].(string)
+		p.Description, _ = entry["description"].(string)
+		p.Required, _ = entry["required"].(bool)
+		p.Schema, _ = entry["schema"].(map[string]any)
+		if p.Name == "" || p.In == "" {
+			return nil, fmt.Errorf("parameters need a name and a location")
+		}
+		params = append(params, p)
+	}
+	return params, nil
+}
+
+func responses(raw any) (map[string]Content, error) {
+	entries, _ := raw.(map[string]any)
+	out := make(map[string]Content, len(entries))
+	for status, entry := range entries {
+		entry, ok := entry.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("response %s must be an object", status)
+		}
+		content := Content{}
+		media, _ := entry["content"].(map[string]any)
+		for mediaType, m := range media {
+			m, _ := m.(map[string]any)
+			schema, _ := m["schema"].(map[string]any)
+			content[mediaType] = schema
+		}
+		out[status] = content
+	}
+	return out, nil
+}
+
+// SchemaNames returns the names of the document's component schemas in
+// order.
+func (s *Spec) SchemaNames() []string {
+	components, _ := s.doc["components"].(map[string]any)
+	schemas, _ := components["schemas"].(map[string]any)
+	names := slices.Collect(maps.Keys(schemas))
+	slices.Sort(names)
+	return names
+}
+
+// Component returns the component schema called name as written in the
+// document, with $refs left in place.
+func (s *Spec) Component(name string) (map[string]any, error) {
+	components, _ := s.doc["components"].(map[string]any)
+	schemas, _ := components["schemas"].(map[string]any)
+	schema, ok := schemas[name].(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("no schema %s in the OpenAPI document", name)
+	}
+	return schema, nil
+}
+
+// RefName returns the component a schema refers to with $ref, if any.
+func RefName(schema map[string]any) (string, bool) {
+	ref, ok := schema["$ref"].(string)
+	if !ok {
+		return "", false
+	}
+	return strings.CutPrefix(ref, schemaRef)
+}
+
+// Schema returns the component schema called name with every $ref inlined
+// and allOf merged, so that it can be used on its own.
+func (s *Spec) Schema(name string, dir Direction) (map[string]any, error) {
+	return s.resolve(map[string]any{"$ref": schemaRef + name}, dir, nil)
+}
+
+// Resolve returns schema, which may be inline or a $ref, with every $ref
+// inlined and allOf merged.
+func (s *Spec) Resolve(schema map[string]any, dir Direction) (map[string]any, error) {
+	return s.resolve(schema, dir, nil)
+}
+
+func (s *Spec) resolve(schema map[string]any, dir Direction, refs []string) (map[string]any, error) {
+	if ref, ok := schema["$ref"].(string); ok {
+		name, ok := strings.CutPrefix(ref, schemaRef)
+		if !ok {
+			return nil, fmt.Errorf("unsupported $ref %s", ref)
+		}
+		if slices.Contains(refs, name) {
+			return nil, fmt.Errorf("schema %s refers to itself", name)
+		}
+		target, err := s.Component(name)
+		if err != nil {
+			return nil, err
+		}
+		return s.resolve(target, dir, append(refs, name))
+	}
+
+	out := make(map[string]any, len(schema))
+	for key, value := range schema {
+		switch key {
+		case "allOf", "properties", "items":
+		case "readOnly":
+			if dir == Response {
+				out[key] = value
+			}
+		case "example":
+			out["examples"] = []any{value}
+		default:
+			out[key] = value
+		}
+	}
+
+	if parts, ok := schema["allOf"].([]any); ok {
+		for _, part := range parts {
+			part, ok := part.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("allOf entries must be schemas")
+			}
+			merged, err := s.resolve(part, dir, refs)
+			if err != nil {
+				return nil, err
+			}
+			merge(out, merged)
+		}
+	}
+
+	if properties, ok := schema["properties"].(map[string]any); ok {
+		resolved := make(map[string]any, len(properties))
+		for name, property := range properties {
+			property, ok := property.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("property %s must be a schema", name)
+			}
+			if dir == Request && property["readOnly"] == true {
+				continue
+			}
+			p, err := s.resolve(property, dir, refs)
+			if err != nil {
+				return nil, fmt.Errorf("property %s: %w", name, err)
+			}
+			resolved[name] = p
+		}
+		merge(out, map[string]any{"properties": resolved})
+	}
+	if required, ok := out["required"].([]any); ok {
+		properties, _ := out["properties"].(map[string]any)
+		kept := make([]any, 0, len(required))
+		for _, name := range required {
+			name, _ := name.(string)
+			if _, ok := properties[name]; ok || properties == nil {
+				kept = append(kept, name)
+			}
+		}
+		out["required"] = kept
+	}
+
+	if items, ok := schema["items"].(map[string]any); ok {
+		resolved, err := s.resolve(items, dir, refs)
+		if err != nil {
+			return nil, fmt.Errorf("items: %w", err)
+		}
+		out["items"] = resolved
+	}
+	return out, nil
+}
+
+// merge folds the object schema src into dst: properties and required
+// entries are combined, and other keywords are kept from whichever schema set
+// them first.
+func merge(dst, src map[string]any) {
+	for key, value := range src {
+		switch key {
+		case "properties":
+			properties, _ := dst[key].(map[string]any)
+			if properties == nil {
+				properties = make(map[string]any)
+			}
+			maps.Copy(properties, value.(map[string]any))
+			dst[key] = properties
+		case "required":
+			required, _ := dst[key].([]any)
+			for _, name := range value.([]any) {
+				if !slices.Contains(required, name) {
+					required = append(required, name)
+				}
+			}
+			dst[key] = required
+		default:
+			if _, ok := dst[key]; !ok {
+				dst[key] = value
+			}
+		}
+	}
+}
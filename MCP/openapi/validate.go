@@ -0,0 +1,263 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+)
+
+// Validator checks requests to the API and the responses to them against
+// the operations of the document, so that tests notice when a client or a
+// fake drifts from it.
+type Validator struct {
+	routes []route
+}
+
+type route struct {
+	op       Operation
+	segments []string // Path segments, with {name} for parameters
+	params   map[string]*jsonschema.Resolved
+	body     *jsonschema.Resolved
+	// responses holds the schemas by status code and media type; nil for
+	// content that is not JSON or not described.
+	responses map[string]map[string]*jsonschema.Resolved
+}
+
+// Validator compiles the schemas of every operation for validation.
+func (s *Spec) Validator() (*Validator, error) {
+	ops, err := s.Operations()
+	if err != nil {
+		return nil, err
+	}
+	v := &Validator{}
+	for _, op := range ops {
+		r := route{
+			op:        op,
+			segments:  strings.Split(strings.Trim(op.Path, "/"), "/"),
+			params:    make(map[string]*jsonschema.Resolved),
+			responses: make(map[string]map[string]*jsonschema.Resolved),
+		}
+		for _, p := range op.Parameters {
+			if r.params[p.Name], err = s.compile(p.Schema, Request); err != nil {
+				return nil, fmt.Errorf("%s: parameter %s: %w", op.ID, p.Name, err)
+			}
+		}
+		if op.Body != nil {
+			if r.body, err = s.compile(op.Body, Request); err != nil {
+				return nil, fmt.Errorf("%s: request body: %w", op.ID, err)
+			}
+		}
+		for status, content := range op.Responses {
+			r.responses[status] = make(map[string]*jsonschema.Resolved)
+			for mediaType, schema := range content {
+				var compiled *jsonschema.Resolved
+				if schema != nil && (mediaType == "application/json" || schema["type"] == "string" && schema["format"] != "binary") {
+					if compiled, err = s.compile(schema, Response); err != nil {
+						return nil, fmt.Errorf("%s: %s response: %w", op.ID, status, err)
+					}
+				}
+				r.responses[status][mediaType] = compiled
+			}
+		}
+		v.routes = append(v.routes, r)
+	}
+	return v, nil
+}
+
+// compile resolves schema and prepares it for validation.
+func (s *Spec) compile(schema map[string]any, dir Direction) (*jsonschema.Resolved, error) {
+	resolved, err := s.Resolve(schema, dir)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(resolved)
+	if err != nil {
+		return nil, err
+	}
+	var js jsonschema.Schema
+	if err := json.Unmarshal(data, &js); err != nil {
+		return nil, err
+	}
+	return js.Resolve(nil)
+}
+
+// Request checks a request, whose path is relative to the API's base URL,
+// and returns the operation it calls.
+func (v *Validator) Request(method, path string, query url.Values, header http.Header, body []byte) (Operation, error) {
+	r, pathParams, err := v.route(method, path)
+	if err != nil {
+		return Operation{}, err
+	}
+	op := r.op
+	fail := func(format string, args ...any) (Operation, error) {
+		return op, fmt.Errorf("%s %s (%s): %s", method, path, op.ID, fmt.Sprintf(format, args...))
+	}
+
+	for _, p := range op.Parameters {
+		switch p.In {
+		case "path":
+			if err := r.params[p.Name].Validate(pathParams[p.Name]); err != nil {
+				return fail("path parameter %s: %v", p.Name, err)
+			}
+		case "query":
+			values, ok := query[p.Name]
+			if !ok {
+				if p.Required {
+					return fail("missing required query parameter %s", p.Name)
+				}
+				continue
+			}
+			if len(values) != 1 {
+				return fail("query parameter %s is repeated", p.Name)
+			}
+			value, err := queryValue(p, values[0])
+			if err != nil {
+				return fail("query parameter %s: %v", p.Name, err)
+			}
+			if err := r.params[p.Name].Validate(value); err != nil {
+				return fail("query parameter %s: %v", p.Name, err)
+			}
+		}
+	}
+	for name := range query {
+		if !slices.ContainsFunc(op.Parameters, func(p Parameter) bool { return p.In == "query" && p.Name == name }) {
+			return fail("undocumented query parameter %s", name)
+		}
+	}
+
+	if op.Secured && !strings.HasPrefix(header.Get("Authorization"), "Bearer ") {
+		return fail("missing bearer token")
+	}
+
+	if r.body == nil {
+		if len(body) > 0 {
+			return fail("the operation takes no request body")
+		}
+		return op, nil
+	}
+	if len(body) == 0 {
+		return op, nil
+	}
+	if mediaType, _, _ := mime.ParseMediaType(header.Get("Content-Type")); mediaType != "application/json" {
+		return fail("request body is %q, not application/json", header.Get("Content-Type"))
+	}
+	var instance any
+	if err := json.Unmarshal(body, &instance); err != nil {
+		return fail("request body: %v", err)
+	}
+	if err := r.body.Validate(instance); err != nil {
+		return fail("request body: %v", err)
+	}
+	return op, nil
+}
+
+// Response checks a response to a call of op.
+func (v *Validator) Response(op Operation, status int, header http.Header, body []byte) error {
+	fail := func(format string, args ...any) error {
+		return fmt.Errorf("%s %s (%s) answered %d: %s", op.Method, op.Path, op.ID, status, fmt.Sprintf(format, args...))
+	}
+	i := slices.IndexFunc(v.routes, func(r route) bool { return r.op.ID == op.ID })
+	if i < 0 {
+		return fail("unknown operation")
+	}
+	responses := v.routes[i].responses
+	content, ok := responses[strconv.Itoa(status)]
+	if !ok {
+		if content, ok = responses["default"]; !ok {
+			return fail("undocumented status")
+		}
+	}
+	if len(content) == 0 {
+		if len(body) > 0 {
+			return fail("the response is documented without content")
+		}
+		return nil
+	}
+	mediaType, _, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		return fail("invalid Content-Type %q", header.Get("Content-Type"))
+	}
+	schema, ok := content[mediaType]
+	if !ok {
+		return fail("undocumented Content-Type %s", mediaType)
+	}
+	if schema == nil {
+		return nil
+	}
+	var instance any = string(body)
+	if mediaType == "application/json" {
+		if err := json.Unmarshal(body, &instance); err != nil {
+			return fail("%v", err)
+		}
+	}
+	if err := schema.Validate(instance); err != nil {
+		return fail("%v", err)
+	}
+	return nil
+}
+
+// route finds the operation for method and path, along with the values of
+// its path parameters.
+func (v *Validator) route(method, path string) (route, map[string]string, error) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	pathFound := false
+	for _, r := range v.routes {
+		params, ok := match(r.segments, segments)
+		if !ok {
+			continue
+		}
+		pathFound = true
+		if r.op.Method == method {
+			return r, params, nil
+		}
+	}
+	if pathFound {
+		return route{}, nil, fmt.Errorf("%s %s: the document has no such operation", method, path)
+	}
+	return route{}, nil, fmt.Errorf("%s %s: the document has no such path", method, path)
+}
+
+func match(pattern, segments []string) (map[string]string, bool) {
+	if len(pattern) != len(segments) {
+		return nil, false
+	}
+	params := make(map[string]string)
+	for i, p := range pattern {
+		if name, ok := strings.CutPrefix(p, "{"); ok {
+			value, err := url.PathUnescape(segments[i])
+			if err != nil || value == "" {
+				return nil, false
+			}
+			params[strings.TrimSuffix(name, "}")] = value
+		} else if p != segments[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+// queryValue converts a query string value to the type of p's schema.
+func queryValue(p Parameter, value string) (any, error) {
+	switch p.Schema["type"] {
+	case "integer":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not an integer", value)
+		}
+		return n, nil
+	case "boolean":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a boolean", value)
+		}
+		return b, nil
+	}
+	return value, nil
+}
@@ -0,0 +1,140 @@
+package openapi
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+const (
+	vaultID = "ionaiwtdvgclrixbt6ztpqcxnq"
+	itemID  = "p7eflcy7f5mk7vg6zrzf5rjjyu"
+)
+
+func connectValidator(t *testing.T) *Validator {
+	t.Helper()
+	spec, err := Load("../../openapi.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := spec.Validator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestValidatorRequest(t *testing.T) {
+	v := connectValidator(t)
+	bearer := http.Header{"Authorization": {"Bearer token"}, "Content-Type": {"application/json"}}
+	itemPath := "/vaults/" + vaultID + "/items/" + itemID
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		query  string
+		header http.Header
+		body   string
+		want   string // Substring of the error; empty when the request is valid
+	}{
+		{name: "list vaults", method: "GET", path: "/vaults", query: `filter=name eq "Prod"`, header: bearer},
+		{name: "health needs no token", method: "GET", path: "/health"},
+		{name: "activity page", method: "GET", path: "/activity", query: "limit=10&offset=20", header: bearer},
+		{name: "patch", method: "PATCH", path: itemPath, header: bearer,
+			body: `[{"op":"replace","path":"/title","value":"New"}]`},
+		{name: "create item", method: "POST", path: "/vaults/" + vaultID + "/items", header: bearer,
+			body: `{"category":"LOGIN","vault":{"id":"` + vaultID + `"},"fields":[{"id":"password","type":"CONCEALED"}]}`},
+
+		{name: "unknown path", method: "GET", path: "/users", header: bearer, want: "no such path"},
+		{name: "unknown method", method: "POST", path: "/vaults", header: bearer, want: "no such operation"},
+		{name: "vault name in path", method: "GET", path: "/vaults/Production", header: bearer, want: "path parameter vaultUuid"},
+		{name: "undocumented query", method: "GET", path: "/vaults", query: "limit=5", header: bearer, want: "undocumented query parameter limit"},
+		{name: "query of the wrong type", method: "GET", path: "/activity", query: "limit=ten", header: bearer, want: "not an integer"},
+		{name: "missing token", method: "GET", path: "/vaults", want: "missing bearer token"},
+		{name: "arguments sent as patch", method: "PATCH", path: itemPath, header: bearer,
+			body: `{"vaultUuid":"` + vaultID + `","items":[{"op":"replace","path":"/title","value":"New"}]}`, want: "request body"},
+		{name: "unsupported patch op", method: "PATCH", path: itemPath, header: bearer,
+			body: `[{"op":"move","from":"/title","path":"/notes"}]`, want: "request body"},
+		{name: "invalid category", method: "POST", path: "/vaults/" + vaultID + "/items", header: bearer,
+			body: `{"category":"RECIPE","vault":{"id":"` + vaultID + `"}}`, want: "request body"},
+		{name: "body on get", method: "GET", path: itemPath, header: bearer, body: `{}`, want: "takes no request body"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			header := tt.header
+			if header == nil {
+				header = http.Header{}
+			}
+			_, err = v.Request(tt.method, tt.path, query, header, []byte(tt.body))
+			switch {
+			case tt.want == "" && err != nil:
+				t.Errorf("valid request rejected: %v", err)
+			case tt.want != "" && err == nil:
+				t.Errorf("invalid request accepted, want an error about %q", tt.want)
+			case tt.want != "" && !strings.Contains(err.Error(), tt.want):
+				t.Errorf("error = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidatorResponse(t *testing.T) {
+	v := connectValidator(t)
+	ops := make(map[string]Operation)
+	for _, r := range v.routes {
+		ops[r.op.ID] = r.op
+	}
+	json := http.Header{"Content-Type": {"application/json"}}
+	text := http.Header{"Content-Type": {"text/plain; charset=utf-8"}}
+
+	tests := []struct {
+		name   string
+		op     string
+		status int
+		header http.Header
+		body   string
+		want   string
+	}{
+		{name: "vault", op: "GetVaultById", status: 200, header: json, body: `{"id":"` + vaultID + `","name":"Prod","items":3}`},
+		{name: "item", op: "GetVaultItemById", status: 200, header: json,
+			body: `{"id":"` + itemID + `","category":"LOGIN","vault":{"id":"` + vaultID + `"},"fields":[{"id":"a","type":"TOTP","totp":"123456"}]}`},
+		{name: "error", op: "GetVaultItemById", status: 404, header: json, body: `{"status":404,"message":"item not found"}`},
+		{name: "heartbeat", op: "GetHeartbeat", status: 200, header: text, body: "."},
+		{name: "deleted", op: "DeleteVaultItem", status: 204},
+		{name: "download", op: "DownloadFileByID", status: 200, header: http.Header{"Content-Type": {"application/octet-stream"}}, body: "\x00\x01"},
+
+		{name: "undocumented status", op: "GetVaults", status: 409, header: json, body: `{}`, want: "undocumented status"},
+		{name: "undocumented media type", op: "GetVaults", status: 200, header: text, body: "[]", want: "undocumented Content-Type"},
+		{name: "wrong type", op: "GetVaultById", status: 200, header: json, body: `{"items":"3"}`, want: "items"},
+		{name: "list as object", op: "GetVaults", status: 200, header: json, body: `{"vaults":[]}`, want: "answered 200"},
+		{name: "missing required", op: "GetVaultItemById", status: 200, header: json, body: `{"id":"` + itemID + `"}`, want: "answered 200"},
+		{name: "content on 204", op: "DeleteVaultItem", status: 204, body: "{}", want: "without content"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			op, ok := ops[tt.op]
+			if !ok {
+				t.Fatalf("no operation %s", tt.op)
+			}
+			header := tt.header
+			if header == nil {
+				header = http.Header{}
+			}
+			err := v.Response(op, tt.status, header, []byte(tt.body))
+			switch {
+			case tt.want == "" && err != nil:
+				t.Errorf("valid response rejected: %v", err)
+			case tt.want != "" && err == nil:
+				t.Errorf("invalid response accepted, want an error about %q", tt.want)
+			case tt.want != "" && !strings.Contains(err.Error(), tt.want):
+				t.Errorf("error = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
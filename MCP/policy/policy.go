@@ -0,0 +1,184 @@
+// Package policy restricts which tools a deployment or session may use and
+// which vaults each tool may touch.
+package policy
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/1password-connect/mcp-server/models"
+	"gopkg.in/yaml.v3"
+)
+
+// Rule allows or denies names. Deny wins over Allow, and an empty Allow
+// allows everything that is not denied.
+type Rule struct {
+	Allow []string `yaml:"allow"`
+	Deny  []string `yaml:"deny"`
+}
+
+// Policy is loaded from a policy file, for example:
+//
+//	tools:
+//	  deny: [delete_vaults_vaultUuid_items_itemUuid]
+//	vaults:
+//	  allow: [Production, Staging]
+//	toolVaults:
+//	  inject_secret_references:
+//	    allow: [Staging]
+//
+// Vault entries match a vault's UUID, or its name ignoring case. ToolVaults
+// narrows Vaults for individual tools. A nil *Policy allows everything.
+type Policy struct {
+	Tools      Rule            `yaml:"tools"`
+	Vaults     Rule            `yaml:"vaults"`
+	ToolVaults map[string]Rule `yaml:"toolVaults"`
+
+	parent *Policy
+}
+
+// Load reads a policy file. YAML and JSON are both accepted.
+func Load(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy file: %w", err)
+	}
+	p, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid policy file %s: %w", path, err)
+	}
+	return p, nil
+}
+
+// Parse parses a policy in YAML or JSON. Unknown keys are rejected so that a
+// misspelt rule does not silently allow everything.
+func Parse(data []byte) (*Policy, error) {
+	var p Policy
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&p); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// Within returns p narrowed by parent: a tool or vault must be allowed by
+// both. Sessions use it so that their own policy can only restrict the
+// server's.
+func (p *Policy) Within(parent *Policy) *Policy {
+	if p == nil {
+		return parent
+	}
+	if parent == nil {
+		return p
+	}
+	narrowed := *p
+	narrowed.parent = parent.Within(p.parent)
+	return &narrowed
+}
+
+// AllowsTool reports whether the tool called name may be registered and called.
+func (p *Policy) AllowsTool(name string) bool {
+	if p == nil {
+		return true
+	}
+	return p.Tools.permits(func(entry string) bool { return entry == name }) && p.parent.AllowsTool(name)
+}
+
+// AllowsVault reports whether tool may touch vault v. An empty tool stands
+// for access outside any tool call, such as reading resources, and is only
+// subject to Vaults.
+func (p *Policy) AllowsVault(tool string, v models.Vault) bool {
+	if p == nil {
+		return true
+	}
+	match := func(entry string) bool {
+		return entry == v.Id || (v.Name != "" && strings.EqualFold(entry, v.Name))
+	}
+	if !p.Vaults.permits(match) {
+		return false
+	}
+	if rule, ok := p.ToolVaults[tool]; ok && tool != "" && !rule.permits(match) {
+		return false
+	}
+	return p.parent.AllowsVault(tool, v)
+}
+
+// ScopesVaults reports whether AllowsVault can deny any vault.
+func (p *Policy) ScopesVaults() bool {
+	if p == nil {
+		return false
+	}
+	return !p.Vaults.empty() || len(p.ToolVaults) > 0 || p.parent.ScopesVaults()
+}
+
+// NamesVaults reports whether a vault rule refers to vaults by name, so that
+// checking a vault by UUID requires its name too.
+func (p *Policy) NamesVaults() bool {
+	if p == nil {
+		return false
+	}
+	if p.Vaults.names() {
+		return true
+	}
+	for _, rule := range p.ToolVaults {
+		if rule.names() {
+			return true
+		}
+	}
+	return p.parent.NamesVaults()
+}
+
+func (r Rule) permits(match func(string) bool) bool {
+	if slices.ContainsFunc(r.Deny, match) {
+		return false
+	}
+	return len(r.Allow) == 0 || slices.ContainsFunc(r.Allow, match)
+}
+
+func (r Rule) empty() bool {
+	return len(r.Allow) == 0 && len(r.Deny) == 0
+}
+
+func (r Rule) names() bool {
+	return slices.ContainsFunc(r.Allow, isName) || slices.ContainsFunc(r.Deny, isName)
+}
+
+// isName reports whether a vault entry is a name rather than a UUID.
+func isName(entry string) bool {
+	return !models.IsUUID(entry)
+}
+
+// VaultError is returned for a request that touches a vault outside the
+// scope of the calling tool.
+type VaultError struct {
+	Tool  string
+	Vault string
+}
+
+func (e *VaultError) Error() string {
+	if e.Tool == "" {
+		return fmt.Sprintf("vault %s is outside the scope allowed by the server's policy", e.Vault)
+	}
+	return fmt.Sprintf("vault %s is outside the scope the server's policy allows for %s", e.Vault, e.Tool)
+}
+
+type toolKey struct{}
+
+// WithTool returns a copy of ctx recording the tool being called, so that
+// vault checks further down apply that tool's rules.
+func WithTool(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, toolKey{}, name)
+}
+
+// ToolFrom returns the tool recorded by WithTool, or "" outside a tool call.
+func ToolFrom(ctx context.Context) string {
+	name, _ := ctx.Value(toolKey{}).(string)
+	return name
+}
@@ -0,0 +1,123 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/1password-connect/mcp-server/models"
+)
+
+const (
+	productionID = "abcdefghijklmnopqrstuvwxyz"
+	stagingID    = "zyxwvutsrqponmlkjihgfedcba"
+)
+
+var (
+	production = models.Vault{Id: productionID, Name: "Production"}
+	staging    = models.Vault{Id: stagingID, Name: "Staging"}
+)
+
+func TestAllowsTool(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy *Policy
+		tool   string
+		want   bool
+	}{
+		{name: "nil policy", tool: "get_vaults", want: true},
+		{name: "empty allow", policy: &Policy{}, tool: "get_vaults", want: true},
+		{name: "allowed", policy: &Policy{Tools: Rule{Allow: []string{"get_vaults"}}}, tool: "get_vaults", want: true},
+		{name: "not allowed", policy: &Policy{Tools: Rule{Allow: []string{"get_vaults"}}}, tool: "get_activity", want: false},
+		{name: "denied", policy: &Policy{Tools: Rule{Deny: []string{"get_vaults"}}}, tool: "get_vaults", want: false},
+		{name: "deny over allow", policy: &Policy{Tools: Rule{Allow: []string{"get_vaults"}, Deny: []string{"get_vaults"}}}, tool: "get_vaults", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.AllowsTool(tt.tool); got != tt.want {
+				t.Errorf("AllowsTool(%q) = %v, want %v", tt.tool, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAllowsVault(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy *Policy
+		tool   string
+		vault  models.Vault
+		want   bool
+	}{
+		{name: "nil policy", vault: staging, want: true},
+		{name: "allowed by UUID", policy: &Policy{Vaults: Rule{Allow: []string{productionID}}}, vault: production, want: true},
+		{name: "not allowed by UUID", policy: &Policy{Vaults: Rule{Allow: []string{productionID}}}, vault: staging, want: false},
+		{name: "allowed by name ignoring case", policy: &Policy{Vaults: Rule{Allow: []string{"production"}}}, vault: production, want: true},
+		{name: "name unknown", policy: &Policy{Vaults: Rule{Allow: []string{"Production"}}}, vault: models.Vault{Id: productionID}, want: false},
+		{name: "deny by name over allow by UUID", policy: &Policy{Vaults: Rule{Allow: []string{productionID}, Deny: []string{"Production"}}}, vault: production, want: false},
+		{name: "tool narrowed", policy: &Policy{ToolVaults: map[string]Rule{"inject_secret_references": {Allow: []string{"Staging"}}}}, tool: "inject_secret_references", vault: production, want: false},
+		{name: "other tool not narrowed", policy: &Policy{ToolVaults: map[string]Rule{"inject_secret_references": {Allow: []string{"Staging"}}}}, tool: "get_vaults", vault: production, want: true},
+		{name: "no tool not narrowed", policy: &Policy{ToolVaults: map[string]Rule{"inject_secret_references": {Allow: []string{"Staging"}}}}, vault: production, want: true},
+		{name: "tool rule cannot widen", policy: &Policy{Vaults: Rule{Deny: []string{"Staging"}}, ToolVaults: map[string]Rule{"get_vaults": {Allow: []string{"Staging"}}}}, tool: "get_vaults", vault: staging, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.AllowsVault(tt.tool, tt.vault); got != tt.want {
+				t.Errorf("AllowsVault(%q, %s) = %v, want %v", tt.tool, tt.vault.Name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithin(t *testing.T) {
+	server := &Policy{
+		Tools:  Rule{Deny: []string{"delete_vaults_vaultUuid_items_itemUuid"}},
+		Vaults: Rule{Allow: []string{"Production", "Staging"}},
+	}
+	session := &Policy{
+		Tools:  Rule{Allow: []string{"get_vaults", "delete_vaults_vaultUuid_items_itemUuid"}},
+		Vaults: Rule{Allow: []string{"Staging", "Development"}},
+	}
+	narrowed := session.Within(server)
+
+	tools := map[string]bool{"get_vaults": true, "get_activity": false, "delete_vaults_vaultUuid_items_itemUuid": false}
+	for tool, want := range tools {
+		if got := narrowed.AllowsTool(tool); got != want {
+			t.Errorf("AllowsTool(%q) = %v, want %v", tool, got, want)
+		}
+	}
+	vaults := map[string]bool{"Production": false, "Staging": true, "Development": false}
+	for name, want := range vaults {
+		if got := narrowed.AllowsVault("", models.Vault{Id: "x", Name: name}); got != want {
+			t.Errorf("AllowsVault(%q) = %v, want %v", name, got, want)
+		}
+	}
+
+	if got := (*Policy)(nil).Within(server); got != server {
+		t.Errorf("nil.Within(server) = %v, want server", got)
+	}
+	if got := session.Within(nil); got != session {
+		t.Errorf("session.Within(nil) = %v, want session", got)
+	}
+	if !narrowed.ScopesVaults() || !narrowed.NamesVaults() {
+		t.Error("narrowed policy does not scope vaults by name")
+	}
+}
+
+func TestParse(t *testing.T) {
+	p, err := Parse([]byte(`{"vaults": {"allow": ["` + productionID + `"]}, "toolVaults": {"get_vaults": {"deny": ["` + productionID + `"]}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !p.ScopesVaults() || p.NamesVaults() {
+		t.Errorf("policy %+v: want vaults scoped by UUID only", p)
+	}
+	if p.AllowsVault("get_vaults", production) || !p.AllowsVault("get_activity", production) {
+		t.Errorf("policy %+v: want production denied to get_vaults only", p)
+	}
+
+	if _, err := Parse([]byte("vault:\n  allow: [Production]\n")); err == nil {
+		t.Error("misspelt key accepted")
+	}
+	if p, err := Parse(nil); err != nil || p.ScopesVaults() {
+		t.Errorf("Parse(empty) = %+v, %v; want a policy allowing everything", p, err)
+	}
+}
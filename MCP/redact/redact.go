@@ -0,0 +1,106 @@
+// Package redact masks secret values in Connect items and files before they
+// are returned to MCP clients.
+package redact
+
+import (
+	"fmt"
+
+	"github.com/1password-connect/mcp-server/config"
+	"github.com/1password-connect/mcp-server/models"
+)
+
+// Mask replaces redacted values.
+const Mask = "********"
+
+// Policy decides which values are masked, following config.APIConfig.RedactionMode.
+type Policy struct {
+	Mode string
+}
+
+// New returns the policy for a deployment's configuration.
+func New(cfg *config.APIConfig) Policy {
+	if cfg.RedactionMode == "" {
+		return Policy{Mode: config.RedactReveal}
+	}
+	return Policy{Mode: cfg.RedactionMode}
+}
+
+// Sensitive reports whether f holds a secret: a concealed or one-time
+// password field, or a field used as a password.
+func Sensitive(f models.Field) bool {
+	return f.TypeField == "CONCEALED" || f.TypeField == "TOTP" || f.Purpose == "PASSWORD"
+}
+
+// MasksField reports whether the value of f is masked. reveal is the caller's
+// explicit request to see secrets, which only the reveal mode honours.
+func (p Policy) MasksField(f models.Field, reveal bool) bool {
+	switch p.Mode {
+	case config.RedactConcealed:
+		return f.TypeField == "CONCEALED"
+	case config.RedactAlways:
+		return Sensitive(f)
+	default:
+		return Sensitive(f) && !reveal
+	}
+}
+
+// MasksFiles reports whether file contents are masked.
+func (p Policy) MasksFiles(reveal bool) bool {
+	switch p.Mode {
+	case config.RedactConcealed:
+		return false
+	case config.RedactAlways:
+		return true
+	default:
+		return !reveal
+	}
+}
+
+// Item masks the secret values of item in place. Labels, types, purposes and
+// entropy are kept.
+func (p Policy) Item(item *models.FullItem, reveal bool) {
+	for i := range item.Fields {
+		p.Field(&item.Fields[i], reveal)
+	}
+	p.Files(item.Files, reveal)
+}
+
+// Field masks the value of f in place if the policy hides it.
+func (p Policy) Field(f *models.Field, reveal bool) {
+	if !p.MasksField(*f, reveal) {
+		return
+	}
+	if f.Value != "" {
+		f.Value = Mask
+	}
+	if f.Totp != "" {
+		f.Totp = Mask
+	}
+}
+
+// Files masks the inline contents of files in place if the policy hides them.
+func (p Policy) Files(files []models.File, reveal bool) {
+	for i := range files {
+		p.File(&files[i], reveal)
+	}
+}
+
+// File masks the inline contents of f in place if the policy hides them.
+func (p Policy) File(f *models.File, reveal bool) {
+	if f.Content != "" && p.MasksFiles(reveal) {
+		f.Content = Mask
+	}
+}
+
+// Error is returned instead of a value the policy hides.
+type Error struct {
+	What string
+	Mode string
+}
+
+func (e *Error) Error() string {
+	if e.Mode == config.RedactReveal {
+		return fmt.Sprintf("%s is redacted; pass reveal: true to see it", e.What)
+	}
+	return fmt.Sprintf("%s is redacted by the server's REDACTION_MODE=%s", e.What, e.Mode)
+}
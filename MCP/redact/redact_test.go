@@ -0,0 +1,107 @@
+package redact
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/1password-connect/mcp-server/config"
+	"github.com/1password-connect/mcp-server/models"
+)
+
+var (
+	concealed = models.Field{Id: "pin", TypeField: "CONCEALED", Value: "1234", Entropy: 13.3}
+	password  = models.Field{Id: "password", Label: "password", Purpose: "PASSWORD", TypeField: "STRING", Value: "hunter2", Entropy: 40.1}
+	totp      = models.Field{Id: "otp", TypeField: "TOTP", Value: "otpauth://totp/x", Totp: "123456"}
+	plain     = models.Field{Id: "username", Purpose: "USERNAME", TypeField: "STRING", Value: "admin"}
+)
+
+func TestMasksField(t *testing.T) {
+	tests := []struct {
+		mode   string
+		field  models.Field
+		reveal bool
+		want   bool
+	}{
+		{mode: config.RedactReveal, field: concealed, want: true},
+		{mode: config.RedactReveal, field: concealed, reveal: true, want: false},
+		{mode: config.RedactReveal, field: password, want: true},
+		{mode: config.RedactReveal, field: password, reveal: true, want: false},
+		{mode: config.RedactReveal, field: totp, want: true},
+		{mode: config.RedactReveal, field: totp, reveal: true, want: false},
+		{mode: config.RedactReveal, field: plain, want: false},
+
+		{mode: config.RedactAlways, field: concealed, reveal: true, want: true},
+		{mode: config.RedactAlways, field: password, reveal: true, want: true},
+		{mode: config.RedactAlways, field: totp, reveal: true, want: true},
+		{mode: config.RedactAlways, field: plain, reveal: true, want: false},
+
+		{mode: config.RedactConcealed, field: concealed, reveal: true, want: true},
+		{mode: config.RedactConcealed, field: password, want: false},
+		{mode: config.RedactConcealed, field: totp, want: false},
+		{mode: config.RedactConcealed, field: plain, want: false},
+	}
+	for _, tt := range tests {
+		p := Policy{Mode: tt.mode}
+		if got := p.MasksField(tt.field, tt.reveal); got != tt.want {
+			t.Errorf("%s: MasksField(%s, reveal=%v) = %v, want %v", tt.mode, tt.field.Id, tt.reveal, got, tt.want)
+		}
+
+		f := tt.field
+		p.Field(&f, tt.reveal)
+		masked := f.Value == Mask && (tt.field.Totp == "" || f.Totp == Mask)
+		if tt.want != masked || (!tt.want && f.Value != tt.field.Value) {
+			t.Errorf("%s: Field(%s, reveal=%v) = %+v", tt.mode, tt.field.Id, tt.reveal, f)
+		}
+		if f.Id != tt.field.Id || f.Label != tt.field.Label || f.Purpose != tt.field.Purpose || f.Entropy != tt.field.Entropy {
+			t.Errorf("%s: Field(%s) changed more than the value: %+v", tt.mode, tt.field.Id, f)
+		}
+	}
+}
+
+func TestMasksFiles(t *testing.T) {
+	tests := []struct {
+		mode   string
+		reveal bool
+		want   bool
+	}{
+		{mode: config.RedactReveal, want: true},
+		{mode: config.RedactReveal, reveal: true, want: false},
+		{mode: config.RedactAlways, reveal: true, want: true},
+		{mode: config.RedactConcealed, want: false},
+	}
+	for _, tt := range tests {
+		p := Policy{Mode: tt.mode}
+		if got := p.MasksFiles(tt.reveal); got != tt.want {
+			t.Errorf("%s: MasksFiles(reveal=%v) = %v, want %v", tt.mode, tt.reveal, got, tt.want)
+		}
+		item := models.FullItem{Files: []models.File{
+			{Id: "inline", Name: "key.pem", Size: 4, Content: "c2VjcmV0"},
+			{Id: "listed", Name: "dump.bin", Size: 9},
+		}}
+		p.Item(&item, tt.reveal)
+		if masked := item.Files[0].Content == Mask; masked != tt.want {
+			t.Errorf("%s: inline content = %q with reveal=%v", tt.mode, item.Files[0].Content, tt.reveal)
+		}
+		if item.Files[0].Name != "key.pem" || item.Files[0].Size != 4 || item.Files[1].Content != "" {
+			t.Errorf("%s: files = %+v", tt.mode, item.Files)
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	if p := New(&config.APIConfig{}); p.Mode != config.RedactReveal {
+		t.Errorf("default mode = %q, want %q", p.Mode, config.RedactReveal)
+	}
+	if p := New(&config.APIConfig{RedactionMode: config.RedactAlways}); p.Mode != config.RedactAlways {
+		t.Errorf("mode = %q, want %q", p.Mode, config.RedactAlways)
+	}
+}
+
+func TestError(t *testing.T) {
+	if msg := (&Error{What: "The file content", Mode: config.RedactReveal}).Error(); !strings.Contains(msg, "reveal: true") {
+		t.Errorf("reveal mode error = %q, want it to suggest reveal: true", msg)
+	}
+	if msg := (&Error{What: "The file content", Mode: config.RedactAlways}).Error(); !strings.Contains(msg, "REDACTION_MODE=always") {
+		t.Errorf("always mode error = %q, want it to name the mode", msg)
+	}
+}
@@ -4,28 +4,35 @@ import (
 	"github.com/1password-connect/mcp-server/config"
 	"github.com/1password-connect/mcp-server/models"
 	tools_items "github.com/1password-connect/mcp-server/tools/items"
-	tools_metrics "github.com/1password-connect/mcp-server/tools/metrics"
-	tools_vaults "github.com/1password-connect/mcp-server/tools/vaults"
-	tools_files "github.com/1password-connect/mcp-server/tools/files"
-	tools_activity "github.com/1password-connect/mcp-server/tools/activity"
-	tools_health "github.com/1password-connect/mcp-server/tools/health"
+	tools_secrets "github.com/1password-connect/mcp-server/tools/secrets"
 )
 
+// GetAll returns the tools the server registers: one for every operation in
+// the OpenAPI document, listed in registry_gen.go, and the field and secret
+// reference tools, which have no operation of their own. Read-only configurations
+// leave out every tool that modifies vaults, and the policy leaves out the
+// tools it does not allow.
 func GetAll(cfg *config.APIConfig) []models.Tool {
-	return []models.Tool{
-		tools_items.CreateDeletevaultitemTool(cfg),
-		tools_items.CreateGetvaultitembyidTool(cfg),
-		tools_items.CreatePatchvaultitemTool(cfg),
-		tools_items.CreateUpdatevaultitemTool(cfg),
-		tools_metrics.CreateGetprometheusmetricsTool(cfg),
-		tools_vaults.CreateGetvaultsTool(cfg),
-		tools_files.CreateGetitemfilesTool(cfg),
-		tools_vaults.CreateGetvaultbyidTool(cfg),
-		tools_items.CreateGetvaultitemsTool(cfg),
-		tools_items.CreateCreatevaultitemTool(cfg),
-		tools_files.CreateGetdetailsoffilebyidTool(cfg),
-		tools_activity.CreateGetapiactivityTool(cfg),
-		tools_health.CreateGetserverhealthTool(cfg),
-		tools_health.CreateGetheartbeatTool(cfg),
+	tools := append(operationTools(cfg),
+		tools_items.CreateGetitemfieldTool(cfg),
+		tools_items.CreateSetitemfieldTool(cfg),
+		tools_secrets.CreateResolvesecretreferenceTool(cfg),
+		tools_secrets.CreateInjectsecretreferencesTool(cfg),
+	)
+	allowed := tools[:0]
+	for _, tool := range tools {
+		if cfg.ReadOnly && !readOnly(tool) {
+			continue
+		}
+		if cfg.Policy.AllowsTool(tool.Definition.Name) {
+			allowed = append(allowed, tool)
+		}
 	}
+	return allowed
+}
+
+// readOnly reports whether a tool is annotated as leaving vaults unchanged.
+func readOnly(tool models.Tool) bool {
+	hint := tool.Definition.Annotations.ReadOnlyHint
+	return hint != nil && *hint
 }
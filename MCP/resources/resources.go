@@ -0,0 +1,355 @@
+// Package resources exposes Connect vaults and items as MCP resources, so
+// clients can attach them as context without calling tools.
+package resources
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"maps"
+	"sync"
+
+	"github.com/1password-connect/mcp-server/config"
+	"github.com/1password-connect/mcp-server/connect"
+	"github.com/1password-connect/mcp-server/models"
+	"github.com/1password-connect/mcp-server/redact"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Resource URIs. Templates take a vault or item UUID, or a name as accepted by
+// connect.Client.ResolveVault and ResolveItem.
+const (
+	VaultsURI         = "onepassword://vaults"
+	VaultURITemplate  = "onepassword://vaults/{vaultUuid}"
+	ItemURITemplate   = "onepassword://vaults/{vaultUuid}/items/{itemUuid}"
+	jsonMIMEType      = "application/json"
+	vaultResourceName = "Vault: "
+)
+
+// VaultURI returns the resource URI of a vault.
+func VaultURI(vaultID string) string {
+	return fmt.Sprintf("onepassword://vaults/%s", vaultID)
+}
+
+// ItemURI returns the resource URI of an item.
+func ItemURI(vaultID, itemID string) string {
+	return fmt.Sprintf("onepassword://vaults/%s/items/%s", vaultID, itemID)
+}
+
+// Registry serves the vault and item resources of an MCP server and keeps
+// track of the sessions subscribed to them. Reads use the Connect credentials
+// bound to the session, falling back to the server's.
+type Registry struct {
+	srv           *server.MCPServer
+	cfg           *config.APIConfig
+	defaultClient *connect.Client
+	policy        redact.Policy
+
+	mu            sync.Mutex
+	serverVaults  bool                      // SyncVaults was called, so Watch keeps calling it
+	vaults        map[string]bool           // URIs of the server-wide per-vault resources
+	sessionVaults map[string]*sessionVaults // Session ID -> its per-vault resources
+
+	subscriptions
+}
+
+// sessionVaults are the per-vault resources of a session that brought its own
+// credentials, and so may see different vaults than the server's.
+type sessionVaults struct {
+	session server.SessionWithResources
+	client  *connect.Client
+	uris    map[string]bool
+	primed  bool // The vaults were listed once, so changes are notified
+}
+
+// Register adds the vault listing resource and the vault and item resource
+// templates to srv. Through hooks, which must be the hooks srv was created
+// with, it records resource subscriptions and lists the vaults of sessions
+// with their own credentials as resources of those sessions.
+func Register(srv *server.MCPServer, cfg *config.APIConfig, hooks *server.Hooks) *Registry {
+	r := &Registry{
+		srv:           srv,
+		cfg:           cfg,
+		defaultClient: connect.NewClient(cfg),
+		policy:        redact.New(cfg),
+		vaults:        make(map[string]bool),
+		sessionVaults: make(map[string]*sessionVaults),
+		subscriptions: subscriptions{
+			subscribers:    make(map[string]*subscriber),
+			pollInterval:   cmp.Or(cfg.PollInterval, config.DefaultPollInterval),
+			pollMaxBackoff: cmp.Or(cfg.PollMaxBackoff, config.DefaultPollMaxBackoff),
+		},
+	}
+	r.registerSubscriptions(hooks)
+	r.registerSessionVaults(hooks)
+
+	srv.AddResource(mcp.NewResource(VaultsURI, "Vaults",
+		mcp.WithResourceDescription("All vaults the Connect token can access"),
+		mcp.WithMIMEType(jsonMIMEType),
+	), r.readVaults)
+	srv.AddResourceTemplate(mcp.NewResourceTemplate(VaultURITemplate, "Vault",
+		mcp.WithTemplateDescription("Details and metadata of a vault, by UUID or name"),
+		mcp.WithTemplateMIMEType(jsonMIMEType),
+	), r.readVault)
+	srv.AddResourceTemplate(mcp.NewResourceTemplate(ItemURITemplate, "Item",
+		mcp.WithTemplateDescription("An item with its fields, by vault and item UUID or name"),
+		mcp.WithTemplateMIMEType(jsonMIMEType),
+	), r.readItem)
+	return r
+}
+
+// SyncVaults lists the vaults visible to the server's own credentials and
+// registers each as a static resource, removing vaults that have gone. It
+// reports whether the set of vaults changed, in which case every session is
+// sent notifications/resources/list_changed. Only transports with fixed
+// server-wide credentials should call it; Watch then calls it again every
+// PollInterval.
+func (r *Registry) SyncVaults(ctx context.Context) (bool, error) {
+	r.mu.Lock()
+	r.serverVaults = true
+	r.mu.Unlock()
+	current, err := r.vaultResources(ctx, r.defaultClient)
+	if err != nil {
+		return false, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	added, removed := diffVaults(r.vaults, current)
+	// The server sends list_changed to every session as it adds or removes
+	// resources.
+	if len(added) > 0 {
+		r.srv.AddResources(added...)
+	}
+	if len(removed) > 0 {
+		r.srv.DeleteResources(removed...)
+	}
+	r.vaults = resourceURIs(current)
+	if len(added) > 0 || len(removed) > 0 {
+		log.Printf("Vault resources: %d added, %d removed", len(added), len(removed))
+		return true, nil
+	}
+	return false, nil
+}
+
+// registerSessionVaults lists the vaults of each session that is registered
+// with its own credentials as resources of that session, and forgets them
+// when the session ends. Sessions whose transport cannot hold resources of
+// their own, like STDIO, see the vaults registered by SyncVaults instead.
+func (r *Registry) registerSessionVaults(hooks *server.Hooks) {
+	hooks.AddOnRegisterSession(func(ctx context.Context, session server.ClientSession) {
+		withResources, ok := session.(server.SessionWithResources)
+		if !ok {
+			return
+		}
+		if _, ok := config.FromContext(ctx); !ok {
+			return
+		}
+		sv := &sessionVaults{session: withResources, client: connect.ClientFor(ctx, r.defaultClient), uris: make(map[string]bool)}
+		r.mu.Lock()
+		r.sessionVaults[session.SessionID()] = sv
+		r.mu.Unlock()
+		if _, err := r.syncSession(ctx, session.SessionID(), sv); err != nil {
+			log.Printf("Failed to list vault resources for session %s: %v", session.SessionID(), err)
+		}
+	})
+	hooks.AddOnUnregisterSession(func(ctx context.Context, session server.ClientSession) {
+		r.mu.Lock()
+		delete(r.sessionVaults, session.SessionID())
+		r.mu.Unlock()
+	})
+}
+
+// syncSession lists the vaults visible to a session's credentials and
+// registers each as a resource of the session, removing vaults that have
+// gone. It reports whether the set of vaults changed, and notifies the
+// session when it did after the first listing.
+func (r *Registry) syncSession(ctx context.Context, sessionID string, sv *sessionVaults) (bool, error) {
+	current, err := r.vaultResources(ctx, sv.client)
+	if err != nil {
+		return false, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	added, removed := diffVaults(sv.uris, current)
+	primed := sv.primed
+	sv.primed = true
+	if len(added) == 0 && len(removed) == 0 {
+		return false, nil
+	}
+	resources := maps.Clone(sv.session.GetSessionResources())
+	if resources == nil {
+		resources = make(map[string]server.ServerResource, len(added))
+	}
+	for _, resource := range added {
+		resources[resource.Resource.URI] = resource
+	}
+	for _, uri := range removed {
+		delete(resources, uri)
+	}
+	sv.session.SetSessionResources(resources)
+	sv.uris = resourceURIs(current)
+	if primed && sv.session.Initialized() {
+		r.notify(sessionID, mcp.MethodNotificationResourcesListChanged, nil)
+	}
+	return true, nil
+}
+
+// syncVaults refreshes the per-vault resources registered by SyncVaults and
+// those of every session with its own credentials.
+func (r *Registry) syncVaults(ctx context.Context) {
+	r.mu.Lock()
+	serverVaults := r.serverVaults
+	sessions := maps.Clone(r.sessionVaults)
+	r.mu.Unlock()
+
+	if serverVaults {
+		if _, err := r.SyncVaults(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("Failed to list vault resources: %v", err)
+		}
+	}
+	for sessionID, sv := range sessions {
+		if _, err := r.syncSession(ctx, sessionID, sv); err != nil && ctx.Err() == nil {
+			log.Printf("Failed to list vault resources for session %s: %v", sessionID, err)
+		}
+	}
+}
+
+// vaultResources lists the vaults visible to client as static resources, by
+// URI.
+func (r *Registry) vaultResources(ctx context.Context, client *connect.Client) (map[string]server.ServerResource, error) {
+	var vaults []models.Vault
+	if _, err := client.Get(ctx, connect.Path("vaults"), nil, &vaults); err != nil {
+		return nil, err
+	}
+	resources := make(map[string]server.ServerResource, len(vaults))
+	for _, v := range client.FilterVaults(ctx, vaults) {
+		uri := VaultURI(v.Id)
+		resources[uri] = server.ServerResource{
+			Resource: mcp.NewResource(uri, vaultResourceName+v.Name,
+				mcp.WithResourceDescription(v.Description),
+				mcp.WithMIMEType(jsonMIMEType),
+			),
+			Handler: r.staticVault(v.Id),
+		}
+	}
+	return resources, nil
+}
+
+// diffVaults returns the resources in current that are not registered, and
+// the registered URIs that are no longer in current.
+func diffVaults(registered map[string]bool, current map[string]server.ServerResource) (added []server.ServerResource, removed []string) {
+	for uri, resource := range current {
+		if !registered[uri] {
+			added = append(added, resource)
+		}
+	}
+	for uri := range registered {
+		if _, ok := current[uri]; !ok {
+			removed = append(removed, uri)
+		}
+	}
+	return added, removed
+}
+
+func resourceURIs(resources map[string]server.ServerResource) map[string]bool {
+	uris := make(map[string]bool, len(resources))
+	for uri := range resources {
+		uris[uri] = true
+	}
+	return uris
+}
+
+func (r *Registry) readVaults(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	client := connect.ClientFor(ctx, r.defaultClient)
+	var vaults []models.Vault
+	if _, err := client.Get(ctx, connect.Path("vaults"), nil, &vaults); err != nil {
+		return nil, err
+	}
+	return jsonContents(request.Params.URI, client.FilterVaults(ctx, vaults))
+}
+
+func (r *Registry) readVault(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	vaultUuid, err := uriVariable(request, "vaultUuid")
+	if err != nil {
+		return nil, err
+	}
+	return r.vaultContents(ctx, request.Params.URI, vaultUuid)
+}
+
+// staticVault reads a per-vault resource registered by SyncVaults or for a
+// session, whose URI carries no template variables.
+func (r *Registry) staticVault(vaultID string) server.ResourceHandlerFunc {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		return r.vaultContents(ctx, request.Params.URI, vaultID)
+	}
+}
+
+func (r *Registry) vaultContents(ctx context.Context, uri, vaultUuid string) ([]mcp.ResourceContents, error) {
+	client := connect.ClientFor(ctx, r.defaultClient)
+	vaultUuid, err := client.ResolveVault(ctx, vaultUuid)
+	if err != nil {
+		return nil, err
+	}
+	var vault models.Vault
+	if _, err := client.Get(ctx, connect.Path("vaults", vaultUuid), nil, &vault); err != nil {
+		return nil, err
+	}
+	return jsonContents(uri, vault)
+}
+
+func (r *Registry) readItem(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	client := connect.ClientFor(ctx, r.defaultClient)
+	vaultUuid, err := uriVariable(request, "vaultUuid")
+	if err != nil {
+		return nil, err
+	}
+	itemUuid, err := uriVariable(request, "itemUuid")
+	if err != nil {
+		return nil, err
+	}
+	vaultUuid, err = client.ResolveVault(ctx, vaultUuid)
+	if err != nil {
+		return nil, err
+	}
+	itemUuid, err = client.ResolveItem(ctx, vaultUuid, itemUuid)
+	if err != nil {
+		return nil, err
+	}
+	var item models.FullItem
+	if _, err := client.Get(ctx, connect.Path("vaults", vaultUuid, "items", itemUuid), nil, &item); err != nil {
+		return nil, err
+	}
+	// Resource reads take no arguments, so secrets are never revealed here.
+	r.policy.Item(&item, false)
+	return jsonContents(request.Params.URI, item)
+}
+
+// uriVariable returns a variable matched from a resource template.
+func uriVariable(request mcp.ReadResourceRequest, name string) (string, error) {
+	switch v := request.Params.Arguments[name].(type) {
+	case string:
+		return v, nil
+	case []string:
+		if len(v) == 1 {
+			return v[0], nil
+		}
+	}
+	return "", fmt.Errorf("invalid resource URI %q: missing %s", request.Params.URI, name)
+}
+
+func jsonContents(uri string, v any) ([]mcp.ResourceContents, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to format JSON: %w", err)
+	}
+	return []mcp.ResourceContents{mcp.TextResourceContents{
+		URI:      uri,
+		MIMEType: jsonMIMEType,
+		Text:     string(data),
+	}}, nil
+}
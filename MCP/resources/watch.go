@@ -0,0 +1,331 @@
+package resources
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/1password-connect/mcp-server/config"
+	"github.com/1password-connect/mcp-server/connect"
+	"github.com/1password-connect/mcp-server/models"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// subscriber is a session with resource subscriptions, polled with the
+// credentials it had when it subscribed.
+type subscriber struct {
+	cfg    *config.APIConfig
+	client *connect.Client
+	uris   map[string]target
+}
+
+// target is a subscribed resource URI broken into its parts. Vault is empty
+// for the vault listing; Item is empty unless an item is subscribed.
+type target struct {
+	Vault string
+	Item  string
+}
+
+func parseTarget(uri string) (target, bool) {
+	if uri == VaultsURI {
+		return target{}, true
+	}
+	rest, ok := strings.CutPrefix(uri, VaultsURI+"/")
+	if !ok {
+		return target{}, false
+	}
+	parts := strings.Split(rest, "/")
+	switch {
+	case len(parts) == 1 && parts[0] != "":
+		return target{Vault: parts[0]}, true
+	case len(parts) == 3 && parts[0] != "" && parts[1] == "items" && parts[2] != "":
+		return target{Vault: parts[0], Item: parts[2]}, true
+	}
+	return target{}, false
+}
+
+// watchState is what the last successful poll of one vault, or of the vault
+// listing, saw with one session's credentials.
+type watchState struct {
+	vaultID     string
+	version     int               // contentVersion of the vault
+	attrVersion int               // attributeVersion of the vault
+	items       map[string]string // item ID -> version, or vault ID -> versions for the listing
+	titles      map[string]string // item ID -> title
+	primed      bool
+	failures    int
+	nextPoll    time.Time
+}
+
+// changes is the outcome of polling one watchState.
+type changes struct {
+	vault   bool            // vault metadata or contents changed
+	items   map[string]bool // IDs of changed or removed items
+	members bool            // items, or vaults for the listing, were added or removed
+}
+
+// registerSubscriptions tracks resources/subscribe and resources/unsubscribe
+// requests and forgets sessions when they end.
+func (r *Registry) registerSubscriptions(hooks *server.Hooks) {
+	hooks.AddAfterSubscribe(func(ctx context.Context, id any, message *mcp.SubscribeRequest, result *mcp.EmptyResult) {
+		t, ok := parseTarget(message.Params.URI)
+		session := server.ClientSessionFromContext(ctx)
+		if !ok || session == nil {
+			return
+		}
+		cfg, ok := config.FromContext(ctx)
+		if !ok {
+			cfg = r.cfg
+		}
+
+		r.subMu.Lock()
+		defer r.subMu.Unlock()
+		sub, ok := r.subscribers[session.SessionID()]
+		if !ok {
+			sub = &subscriber{cfg: cfg, client: connect.ClientFor(ctx, r.defaultClient), uris: make(map[string]target)}
+			r.subscribers[session.SessionID()] = sub
+		}
+		sub.uris[message.Params.URI] = t
+	})
+	hooks.AddAfterUnsubscribe(func(ctx context.Context, id any, message *mcp.UnsubscribeRequest, result *mcp.EmptyResult) {
+		session := server.ClientSessionFromContext(ctx)
+		if session == nil {
+			return
+		}
+		r.subMu.Lock()
+		defer r.subMu.Unlock()
+		if sub, ok := r.subscribers[session.SessionID()]; ok {
+			delete(sub.uris, message.Params.URI)
+			if len(sub.uris) == 0 {
+				delete(r.subscribers, session.SessionID())
+			}
+		}
+	})
+	hooks.AddOnUnregisterSession(func(ctx context.Context, session server.ClientSession) {
+		r.subMu.Lock()
+		delete(r.subscribers, session.SessionID())
+		r.subMu.Unlock()
+	})
+}
+
+// Watch polls the subscribed vaults every PollInterval until ctx is done and
+// notifies subscribed sessions of changes. A vault is only listed again when
+// its contentVersion changes; vaults that fail to poll are retried with
+// exponential backoff up to PollMaxBackoff. Each poll also brings the
+// per-vault resources up to date, sending list_changed only when vaults were
+// added or removed.
+func (r *Registry) Watch(ctx context.Context) {
+	ticker := time.NewTicker(r.pollInterval)
+	defer ticker.Stop()
+	states := make(map[string]*watchState)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.syncVaults(ctx)
+			r.poll(ctx, states)
+		}
+	}
+}
+
+func (r *Registry) poll(ctx context.Context, states map[string]*watchState) {
+	type watcher struct {
+		sessionID string
+		uri       string
+		target    target
+	}
+	type group struct {
+		client   *connect.Client
+		vault    string
+		watchers []watcher
+	}
+
+	// Snapshot the subscriptions, grouped by session credentials and vault.
+	groups := make(map[string]*group)
+	r.subMu.Lock()
+	for sessionID, sub := range r.subscribers {
+		for uri, t := range sub.uris {
+			key := fmt.Sprintf("%p/%s", sub.cfg, t.Vault)
+			g, ok := groups[key]
+			if !ok {
+				g = &group{client: sub.client, vault: t.Vault}
+				groups[key] = g
+			}
+			g.watchers = append(g.watchers, watcher{sessionID: sessionID, uri: uri, target: t})
+		}
+	}
+	r.subMu.Unlock()
+
+	for key := range states {
+		if _, ok := groups[key]; !ok {
+			delete(states, key)
+		}
+	}
+
+	now := time.Now()
+	for key, g := range groups {
+		state, ok := states[key]
+		if !ok {
+			state = &watchState{}
+			states[key] = state
+		}
+		if now.Before(state.nextPoll) {
+			continue
+		}
+
+		var changed changes
+		var err error
+		if g.vault == "" {
+			changed, err = pollVaults(ctx, g.client, state)
+		} else {
+			changed, err = pollVault(ctx, g.client, g.vault, state)
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			state.failures++
+			backoff := min(r.pollInterval<<min(state.failures, 16), r.pollMaxBackoff)
+			state.nextPoll = now.Add(backoff)
+			log.Printf("Failed to poll %s, retrying in %s: %v", VaultsURI+"/"+g.vault, backoff, err)
+			continue
+		}
+		state.failures = 0
+		state.nextPoll = time.Time{}
+
+		// Items added to or removed from a vault change the vault's resource,
+		// not the resource list; syncVaults sends list_changed when the set of
+		// vaults changes.
+		for _, w := range g.watchers {
+			updated := changed.vault
+			if w.target.Item != "" {
+				updated = changed.items[state.itemID(w.target.Item)]
+			}
+			if updated {
+				r.notify(w.sessionID, mcp.MethodNotificationResourceUpdated, map[string]any{"uri": w.uri})
+			}
+		}
+	}
+}
+
+func (r *Registry) notify(sessionID, method string, params map[string]any) {
+	if err := r.srv.SendNotificationToSpecificClient(sessionID, method, params); err != nil {
+		log.Printf("Failed to send %s to session %s: %v", method, sessionID, err)
+	}
+}
+
+// pollVault checks a vault's contentVersion and, when it moved, diffs the
+// versions of its items against the previous poll.
+func pollVault(ctx context.Context, client *connect.Client, vault string, state *watchState) (changes, error) {
+	if state.vaultID == "" {
+		id, err := client.ResolveVault(ctx, vault)
+		if err != nil {
+			return changes{}, err
+		}
+		state.vaultID = id
+	}
+
+	var v models.Vault
+	if _, err := client.Get(ctx, connect.Path("vaults", state.vaultID), nil, &v); err != nil {
+		return changes{}, err
+	}
+	attrsChanged := state.primed && v.Attributeversion != state.attrVersion
+	state.attrVersion = v.Attributeversion
+	if state.primed && v.Contentversion == state.version {
+		return changes{vault: attrsChanged}, nil
+	}
+
+	var items []models.Item
+	if _, err := client.Get(ctx, connect.Path("vaults", state.vaultID, "items"), nil, &items); err != nil {
+		return changes{}, err
+	}
+	if state.titles == nil {
+		state.titles = make(map[string]string, len(items))
+	}
+	versions := make(map[string]string, len(items))
+	for _, it := range items {
+		versions[it.Id] = strconv.Itoa(it.Version)
+		// Titles of removed items are kept so that a subscription made by
+		// title is still told about the removal.
+		state.titles[it.Id] = it.Title
+	}
+
+	changed := diff(state, versions)
+	changed.vault = state.primed
+	state.version = v.Contentversion
+	state.items = versions
+	state.primed = true
+	return changed, nil
+}
+
+// pollVaults diffs the vault listing against the previous poll.
+func pollVaults(ctx context.Context, client *connect.Client, state *watchState) (changes, error) {
+	var vaults []models.Vault
+	if _, err := client.Get(ctx, connect.Path("vaults"), nil, &vaults); err != nil {
+		return changes{}, err
+	}
+	vaults = client.FilterVaults(ctx, vaults)
+	versions := make(map[string]string, len(vaults))
+	for _, v := range vaults {
+		versions[v.Id] = fmt.Sprintf("%d/%d", v.Contentversion, v.Attributeversion)
+	}
+
+	changed := diff(state, versions)
+	changed.vault = len(changed.items) > 0 || changed.members
+	state.items = versions
+	state.primed = true
+	return changed, nil
+}
+
+// diff compares versions with the ones seen by the previous poll. The first
+// poll only records them.
+func diff(state *watchState, versions map[string]string) changes {
+	changed := changes{items: make(map[string]bool)}
+	if !state.primed {
+		return changed
+	}
+	for id, version := range versions {
+		old, ok := state.items[id]
+		if !ok {
+			changed.members = true
+		} else if old != version {
+			changed.items[id] = true
+		}
+	}
+	for id := range state.items {
+		if _, ok := versions[id]; !ok {
+			changed.members = true
+			changed.items[id] = true
+		}
+	}
+	return changed
+}
+
+// itemID maps an item reference from a resource URI, an ID or a title, to the
+// ID seen by the last poll.
+func (s *watchState) itemID(item string) string {
+	if _, ok := s.items[item]; ok {
+		return item
+	}
+	for id, title := range s.titles {
+		if title == item {
+			return id
+		}
+	}
+	return item
+}
+
+// subscriptions holds the fields of Registry used for resource subscriptions.
+type subscriptions struct {
+	subMu       sync.Mutex
+	subscribers map[string]*subscriber // session ID -> subscriptions
+
+	pollInterval   time.Duration
+	pollMaxBackoff time.Duration
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/1password-connect/mcp-server/models"
+	"github.com/1password-connect/mcp-server/resources"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// TestVaultResources checks that each vault the session's credentials can
+// access is listed as a resource, and that the listing follows vaults added
+// while the session is open.
+func TestVaultResources(t *testing.T) {
+	for name, newClient := range transports {
+		t.Run(name, func(t *testing.T) {
+			f := newFixture(t)
+			cfg := f.connect.APIConfig()
+			cfg.PollInterval = 10 * time.Millisecond
+			c := newClient(t, cfg)
+			listChanged := make(chan struct{}, 1)
+			c.OnNotification(func(n mcp.JSONRPCNotification) {
+				if n.Method == mcp.MethodNotificationResourcesListChanged {
+					select {
+					case listChanged <- struct{}{}:
+					default:
+					}
+				}
+			})
+			ctx := context.Background()
+
+			uris := listResources(t, c)
+			vaultURI := resources.VaultURI(f.vault.Id)
+			if !slices.Contains(uris, resources.VaultsURI) || !slices.Contains(uris, vaultURI) || len(uris) != 3 {
+				t.Fatalf("resources = %v, want the listing and both vaults", uris)
+			}
+			request := mcp.ReadResourceRequest{}
+			request.Params.URI = vaultURI
+			read, err := c.ReadResource(ctx, request)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if contents, ok := read.Contents[0].(mcp.TextResourceContents); !ok || contents.URI != vaultURI {
+				t.Errorf("contents = %+v", read.Contents)
+			}
+
+			added := f.connect.Store.AddVault("Development", "")
+			select {
+			case <-listChanged:
+			case <-time.After(5 * time.Second):
+				t.Fatal("no list_changed notification after a vault was added")
+			}
+			if uris := listResources(t, c); !slices.Contains(uris, resources.VaultURI(added.Id)) {
+				t.Errorf("resources = %v, want the added vault", uris)
+			}
+		})
+	}
+}
+
+// TestResourceSubscriptions checks that a session subscribed to a vault is
+// told the vault was updated when an item is added to it, and that the
+// resource list is not reported as changed.
+func TestResourceSubscriptions(t *testing.T) {
+	for name, newClient := range transports {
+		t.Run(name, func(t *testing.T) {
+			f := newFixture(t)
+			cfg := f.connect.APIConfig()
+			cfg.PollInterval = 10 * time.Millisecond
+			c := newClient(t, cfg)
+			notifications := make(chan mcp.JSONRPCNotification, 16)
+			c.OnNotification(func(n mcp.JSONRPCNotification) {
+				select {
+				case notifications <- n:
+				default:
+				}
+			})
+
+			vaultURI := resources.VaultURI(f.vault.Id)
+			request := mcp.SubscribeRequest{}
+			request.Params.URI = vaultURI
+			if err := c.Subscribe(context.Background(), request); err != nil {
+				t.Fatal(err)
+			}
+			// Let the first poll record the vault's items.
+			time.Sleep(100 * time.Millisecond)
+
+			if _, err := f.connect.Store.AddItem(f.vault.Id, models.FullItem{Title: "Staging", Category: "LOGIN"}); err != nil {
+				t.Fatal(err)
+			}
+			timeout := time.After(5 * time.Second)
+			for updated := false; !updated; {
+				select {
+				case n := <-notifications:
+					switch n.Method {
+					case mcp.MethodNotificationResourcesListChanged:
+						t.Fatal("list_changed sent after an item was added")
+					case mcp.MethodNotificationResourceUpdated:
+						if uri := n.Params.AdditionalFields["uri"]; uri != vaultURI {
+							t.Fatalf("resources/updated for %v, want %s", uri, vaultURI)
+						}
+						updated = true
+					}
+				case <-timeout:
+					t.Fatal("no resources/updated notification after an item was added")
+				}
+			}
+			// A few more polls must not report the list as changed either.
+			deadline := time.After(100 * time.Millisecond)
+			for {
+				select {
+				case n := <-notifications:
+					if n.Method == mcp.MethodNotificationResourcesListChanged {
+						t.Fatal("list_changed sent after an item was added")
+					}
+				case <-deadline:
+					return
+				}
+			}
+		})
+	}
+}
+
+func listResources(t *testing.T, c interface {
+	ListResources(context.Context, mcp.ListResourcesRequest) (*mcp.ListResourcesResult, error)
+}) []string {
+	t.Helper()
+	listed, err := c.ListResources(context.Background(), mcp.ListResourcesRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var uris []string
+	for _, resource := range listed.Resources {
+		uris = append(uris, resource.URI)
+	}
+	return uris
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+
+	"github.com/1password-connect/mcp-server/connect"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// attemptsMetaKey reports in a tool result's _meta how many Connect requests
+// the call made when some of them had to be retried.
+const attemptsMetaKey = "io.1password.connect-mcp/attempts"
+
+// reportAttempts is a tool middleware that counts the Connect requests made by
+// each call and adds the counts to the result when any were retried.
+func reportAttempts(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx, counter := connect.WithAttemptCounter(ctx)
+		result, err := next(ctx, request)
+		if result == nil || counter.Retries() == 0 {
+			return result, err
+		}
+		if result.Meta == nil {
+			result.Meta = &mcp.Meta{}
+		}
+		if result.Meta.AdditionalFields == nil {
+			result.Meta.AdditionalFields = make(map[string]any)
+		}
+		result.Meta.AdditionalFields[attemptsMetaKey] = map[string]int64{
+			"requests": counter.Requests(),
+			"retries":  counter.Retries(),
+		}
+		return result, err
+	}
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"context"
+
+	"github.com/1password-connect/mcp-server/config"
+	"github.com/1password-connect/mcp-server/policy"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// scopeTool is a tool middleware that records which tool is being called, so
+// that the Connect client applies the policy's vault rules for that tool.
+func scopeTool(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return next(policy.WithTool(ctx, request.Params.Name), request)
+	}
+}
+
+// filterTools hides the tools a session's policy does not allow, from both
+// tools/list and tools/call. The server's own policy has already been applied
+// by GetAll.
+func filterTools(ctx context.Context, tools []mcp.Tool) []mcp.Tool {
+	cfg, ok := config.FromContext(ctx)
+	if !ok || cfg.Policy == nil {
+		return tools
+	}
+	allowed := make([]mcp.Tool, 0, len(tools))
+	for _, tool := range tools {
+		if cfg.Policy.AllowsTool(tool.Name) {
+			allowed = append(allowed, tool)
+		}
+	}
+	return allowed
+}
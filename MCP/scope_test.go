@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/1password-connect/mcp-server/config"
+	"github.com/1password-connect/mcp-server/models"
+	"github.com/1password-connect/mcp-server/policy"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// TestSessionPolicy checks that a POLICY header can only narrow the server's
+// policy.
+func TestSessionPolicy(t *testing.T) {
+	cfg := &config.APIConfig{Policy: &policy.Policy{
+		Tools:  policy.Rule{Deny: []string{"delete_vaults_vaultUuid_items_itemUuid"}},
+		Vaults: policy.Rule{Deny: []string{"Production"}},
+	}}
+	tests := []struct {
+		name   string
+		header string
+		err    string // Substring of the error
+	}{
+		{name: "none"},
+		{name: "narrower", header: `{"tools": {"allow": ["get_vaults", "delete_vaults_vaultUuid_items_itemUuid"]}, "vaults": {"allow": ["Production", "Staging"]}}`},
+		{name: "invalid", header: `{"tool": {"allow": ["get_vaults"]}}`, err: "invalid POLICY header"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/mcp", nil)
+			r.Header.Set("API_BASE_URL", "http://connect.example/v1")
+			r.Header.Set("BEARER_TOKEN", "t")
+			if tt.header != "" {
+				r.Header.Set("POLICY", tt.header)
+			}
+			apiCfg, err := apiConfigFromHeaders(r, cfg)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Errorf("error = %v, want it to mention %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			p := apiCfg.Policy
+			if p.AllowsTool("delete_vaults_vaultUuid_items_itemUuid") || p.AllowsVault("", models.Vault{Id: "x", Name: "Production"}) {
+				t.Error("session policy widened the server's")
+			}
+			if tt.header == "" && p != cfg.Policy {
+				t.Errorf("policy = %+v, want the server's", p)
+			}
+			if tt.header != "" && (p.AllowsTool("get_activity") || p.AllowsVault("", models.Vault{Id: "x", Name: "Development"})) {
+				t.Error("session policy did not narrow the server's")
+			}
+		})
+	}
+}
+
+func TestFilterTools(t *testing.T) {
+	tools := []mcp.Tool{{Name: "get_vaults"}, {Name: "get_activity"}, {Name: "inject_secret_references"}}
+	names := func(tools []mcp.Tool) []string {
+		var names []string
+		for _, tool := range tools {
+			names = append(names, tool.Name)
+		}
+		return names
+	}
+
+	if got := filterTools(context.Background(), tools); len(got) != len(tools) {
+		t.Errorf("without a session = %v, want every tool", names(got))
+	}
+	cfg := &config.APIConfig{Policy: &policy.Policy{Tools: policy.Rule{Deny: []string{"get_activity"}}}}
+	got := names(filterTools(config.WithContext(context.Background(), cfg), tools))
+	if !slices.Equal(got, []string{"get_vaults", "inject_secret_references"}) {
+		t.Errorf("with a session policy = %v, want get_activity left out", got)
+	}
+}
@@ -0,0 +1,220 @@
+package secretref
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/1password-connect/mcp-server/connect"
+	"github.com/1password-connect/mcp-server/models"
+	"github.com/1password-connect/mcp-server/redact"
+)
+
+// placeholder matches a secret reference in double braces, e.g.
+// {{ op://prod/db/password }}.
+var placeholder = regexp.MustCompile(`\{\{\s*(op://[^{}]*?)\s*\}\}`)
+
+// maxConcurrentFetches bounds the Connect requests Inject has in flight.
+const maxConcurrentFetches = 4
+
+// InjectOptions controls Inject.
+type InjectOptions struct {
+	Strict bool          // Fail unless every reference resolves
+	DryRun bool          // List the references without fetching any values
+	Policy redact.Policy // Secret values the policy masks are left unresolved
+	Reveal bool          // The caller asked for secret values, see redact.Policy
+}
+
+// Unresolved is a reference Inject could not resolve and left in place.
+type Unresolved struct {
+	Reference string `json:"reference"`
+	Error     string `json:"error"`
+}
+
+// InjectResult is the outcome of Inject.
+type InjectResult struct {
+	Text       string       `json:"text"`
+	References []string     `json:"references"` // Distinct references in order of appearance
+	Unresolved []Unresolved `json:"unresolved,omitempty"`
+}
+
+// UnresolvedError is returned by Inject in strict mode when any reference
+// could not be resolved.
+type UnresolvedError struct {
+	Unresolved []Unresolved
+}
+
+func (e *UnresolvedError) Error() string {
+	msgs := make([]string, len(e.Unresolved))
+	for i, u := range e.Unresolved {
+		msgs[i] = fmt.Sprintf("%s: %s", u.Reference, u.Error)
+	}
+	return fmt.Sprintf("%d secret reference(s) could not be resolved: %s", len(e.Unresolved), strings.Join(msgs, "; "))
+}
+
+// Inject replaces every {{ op://... }} placeholder in template with the value
+// it refers to. Each vault and item is resolved and fetched once however often
+// it is referenced, and distinct items are fetched concurrently. Placeholders
+// that cannot be resolved are left as they are and reported in the result,
+// unless opts.Strict makes them an error.
+func Inject(ctx context.Context, client *connect.Client, template string, opts InjectOptions) (*InjectResult, error) {
+	result := &InjectResult{Text: template, References: []string{}}
+
+	refs := make(map[string]Reference)
+	failed := make(map[string]error)
+	for _, m := range placeholder.FindAllStringSubmatch(template, -1) {
+		raw := m[1]
+		if _, seen := refs[raw]; seen {
+			continue
+		}
+		if _, seen := failed[raw]; seen {
+			continue
+		}
+		result.References = append(result.References, raw)
+		ref, err := Parse(raw)
+		if err != nil {
+			failed[raw] = err
+			continue
+		}
+		refs[raw] = ref
+	}
+
+	values := make(map[string]string)
+	if !opts.DryRun {
+		items := fetchItems(ctx, client, refs)
+		for raw, ref := range refs {
+			fetched := items[itemKey(ref)]
+			if fetched.err != nil {
+				failed[raw] = fetched.err
+				continue
+			}
+			field, err := ref.FindField(fetched.item)
+			if err != nil {
+				failed[raw] = err
+				continue
+			}
+			if ref.Secret() && opts.Policy.MasksField(*field, opts.Reveal) {
+				failed[raw] = &redact.Error{What: "The value of " + raw, Mode: opts.Policy.Mode}
+				continue
+			}
+			value, err := ref.attribute(field)
+			if err != nil {
+				failed[raw] = err
+				continue
+			}
+			values[raw] = value
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, raw := range result.References {
+		if err, ok := failed[raw]; ok {
+			result.Unresolved = append(result.Unresolved, Unresolved{Reference: raw, Error: err.Error()})
+		}
+	}
+	if opts.Strict && len(result.Unresolved) > 0 {
+		return nil, &UnresolvedError{Unresolved: result.Unresolved}
+	}
+	if opts.DryRun {
+		return result, nil
+	}
+
+	result.Text = placeholder.ReplaceAllStringFunc(template, func(match string) string {
+		raw := placeholder.FindStringSubmatch(match)[1]
+		if value, ok := values[raw]; ok {
+			return value
+		}
+		return match
+	})
+	return result, nil
+}
+
+type fetchedItem struct {
+	item *models.FullItem
+	err  error
+}
+
+func itemKey(ref Reference) string {
+	return ref.Vault + "/" + ref.Item
+}
+
+// fetchItems resolves the distinct vaults, then fetches the distinct items,
+// of refs.
+func fetchItems(ctx context.Context, client *connect.Client, refs map[string]Reference) map[string]fetchedItem {
+	var vaultNames []string
+	vaults := make(map[string]fetchedVault)
+	for _, ref := range refs {
+		if _, ok := vaults[ref.Vault]; !ok {
+			vaults[ref.Vault] = fetchedVault{}
+			vaultNames = append(vaultNames, ref.Vault)
+		}
+	}
+	var mu sync.Mutex
+	parallel(len(vaultNames), func(i int) {
+		id, err := client.ResolveVault(ctx, vaultNames[i])
+		mu.Lock()
+		vaults[vaultNames[i]] = fetchedVault{id: id, err: err}
+		mu.Unlock()
+	})
+
+	var itemRefs []Reference
+	items := make(map[string]fetchedItem)
+	for _, ref := range refs {
+		key := itemKey(ref)
+		if _, ok := items[key]; ok {
+			continue
+		}
+		if vault := vaults[ref.Vault]; vault.err != nil {
+			items[key] = fetchedItem{err: vault.err}
+			continue
+		}
+		items[key] = fetchedItem{}
+		itemRefs = append(itemRefs, ref)
+	}
+	parallel(len(itemRefs), func(i int) {
+		ref := itemRefs[i]
+		item, err := fetchItem(ctx, client, vaults[ref.Vault].id, ref.Item)
+		mu.Lock()
+		items[itemKey(ref)] = fetchedItem{item: item, err: err}
+		mu.Unlock()
+	})
+	return items
+}
+
+type fetchedVault struct {
+	id  string
+	err error
+}
+
+func fetchItem(ctx context.Context, client *connect.Client, vaultID, item string) (*models.FullItem, error) {
+	itemID, err := client.ResolveItem(ctx, vaultID, item)
+	if err != nil {
+		return nil, err
+	}
+	var full models.FullItem
+	if _, err := client.Get(ctx, connect.Path("vaults", vaultID, "items", itemID), nil, &full); err != nil {
+		return nil, err
+	}
+	return &full, nil
+}
+
+// parallel calls fn for 0..n-1 with at most maxConcurrentFetches calls
+// running at once, and returns when all have finished.
+func parallel(n int, fn func(i int)) {
+	sem := make(chan struct{}, maxConcurrentFetches)
+	var wg sync.WaitGroup
+	for i := range n {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(i)
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,244 @@
+// Package secretref parses and resolves 1Password secret references of the
+// form op://vault/item[/section]/field[?attribute=...].
+package secretref
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/1password-connect/mcp-server/connect"
+	"github.com/1password-connect/mcp-server/models"
+)
+
+const scheme = "op://"
+
+// Attributes a reference may select with ?attribute=. AttrValue is the default.
+const (
+	AttrValue   = "value"
+	AttrType    = "type"
+	AttrID      = "id"
+	AttrLabel   = "label"
+	AttrPurpose = "purpose"
+	AttrOTP     = "otp"
+)
+
+var attributes = map[string]string{
+	AttrValue:   AttrValue,
+	AttrType:    AttrType,
+	AttrID:      AttrID,
+	AttrLabel:   AttrLabel,
+	AttrPurpose: AttrPurpose,
+	AttrOTP:     AttrOTP,
+	"totp":      AttrOTP,
+}
+
+// Reference is a parsed secret reference. Vault and Item are names or IDs;
+// Section and Field are labels or IDs. Section is empty when the reference
+// does not name one.
+type Reference struct {
+	Vault     string
+	Item      string
+	Section   string
+	Field     string
+	Attribute string
+}
+
+// SyntaxError is returned by Parse for a malformed reference.
+type SyntaxError struct {
+	Reference string
+	Reason    string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("invalid secret reference %q: %s", e.Reference, e.Reason)
+}
+
+// Parse parses a secret reference.
+func Parse(ref string) (Reference, error) {
+	fail := func(format string, args ...any) (Reference, error) {
+		return Reference{}, &SyntaxError{Reference: ref, Reason: fmt.Sprintf(format, args...)}
+	}
+
+	rest, ok := strings.CutPrefix(ref, scheme)
+	if !ok {
+		return fail("must start with %s", scheme)
+	}
+	path, rawQuery, _ := strings.Cut(rest, "?")
+
+	parts := strings.Split(path, "/")
+	if len(parts) < 3 || len(parts) > 4 {
+		return fail("expected op://vault/item/field or op://vault/item/section/field")
+	}
+	for _, p := range parts {
+		if p == "" {
+			return fail("empty path segment")
+		}
+	}
+
+	r := Reference{Vault: parts[0], Item: parts[1], Field: parts[len(parts)-1], Attribute: AttrValue}
+	if len(parts) == 4 {
+		r.Section = parts[2]
+	}
+
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return fail("malformed query: %v", err)
+	}
+	for key, values := range query {
+		if key != "attribute" {
+			return fail("unsupported query parameter %q", key)
+		}
+		attr, ok := attributes[strings.ToLower(values[len(values)-1])]
+		if !ok {
+			return fail("unsupported attribute %q: must be one of value, type, id, label, purpose or otp", values[len(values)-1])
+		}
+		r.Attribute = attr
+	}
+	return r, nil
+}
+
+// String formats r as a secret reference.
+func (r Reference) String() string {
+	parts := []string{r.Vault, r.Item}
+	if r.Section != "" {
+		parts = append(parts, r.Section)
+	}
+	s := scheme + strings.Join(append(parts, r.Field), "/")
+	if r.Attribute != "" && r.Attribute != AttrValue {
+		s += "?attribute=" + r.Attribute
+	}
+	return s
+}
+
+// Resolve fetches the item r refers to and returns the selected value.
+func (r Reference) Resolve(ctx context.Context, client *connect.Client) (string, error) {
+	item, err := r.FetchItem(ctx, client)
+	if err != nil {
+		return "", err
+	}
+	return r.Select(item)
+}
+
+// FetchItem resolves r's vault and item and fetches the full item.
+func (r Reference) FetchItem(ctx context.Context, client *connect.Client) (*models.FullItem, error) {
+	vaultID, err := client.ResolveVault(ctx, r.Vault)
+	if err != nil {
+		return nil, err
+	}
+	return fetchItem(ctx, client, vaultID, r.Item)
+}
+
+// Select returns the attribute of the field r refers to within item.
+func (r Reference) Select(item *models.FullItem) (string, error) {
+	field, err := r.FindField(item)
+	if err != nil {
+		return "", err
+	}
+	return r.attribute(field)
+}
+
+// Secret reports whether r selects a secret value of its field rather than
+// metadata such as its type or label.
+func (r Reference) Secret() bool {
+	return r.Attribute == AttrValue || r.Attribute == AttrOTP
+}
+
+// FindField returns the field r refers to within item. Sections and fields
+// match by ID first and then by label, ignoring case.
+func (r Reference) FindField(item *models.FullItem) (*models.Field, error) {
+	fields, err := SectionFields(item, r.Section)
+	if err != nil {
+		return nil, err
+	}
+	return MatchField(fields, r.Field)
+}
+
+func (r Reference) attribute(field *models.Field) (string, error) {
+	switch r.Attribute {
+	case AttrType:
+		return field.TypeField, nil
+	case AttrID:
+		return field.Id, nil
+	case AttrLabel:
+		return field.Label, nil
+	case AttrPurpose:
+		return field.Purpose, nil
+	case AttrOTP:
+		if field.TypeField != "TOTP" {
+			return "", fmt.Errorf("field %q is of type %s and has no one-time password", r.Field, field.TypeField)
+		}
+		if field.Totp == "" {
+			return "", fmt.Errorf("the Connect server returned no one-time password for field %q", r.Field)
+		}
+		return field.Totp, nil
+	default:
+		return field.Value, nil
+	}
+}
+
+// SectionFields returns the fields of item within section, given by ID or
+// label, or all of its fields when section is empty.
+func SectionFields(item *models.FullItem, section string) ([]models.Field, error) {
+	if section == "" {
+		return item.Fields, nil
+	}
+	sectionID, err := findSection(item, section)
+	if err != nil {
+		return nil, err
+	}
+	var fields []models.Field
+	for _, f := range item.Fields {
+		if id, _ := f.Section["id"].(string); id == sectionID {
+			fields = append(fields, f)
+		}
+	}
+	return fields, nil
+}
+
+func findSection(item *models.FullItem, name string) (string, error) {
+	var byLabel []connect.Candidate
+	for _, s := range item.Sections {
+		id, _ := s["id"].(string)
+		label, _ := s["label"].(string)
+		if id == name {
+			return id, nil
+		}
+		if strings.EqualFold(label, name) {
+			byLabel = append(byLabel, connect.Candidate{ID: id, Name: label})
+		}
+	}
+	switch len(byLabel) {
+	case 0:
+		return "", &connect.ResolveError{Kind: connect.KindNotFound, Resource: "section", Name: name}
+	case 1:
+		return byLabel[0].ID, nil
+	}
+	return "", &connect.ResolveError{Kind: connect.KindAmbiguous, Resource: "section", Name: name, Candidates: byLabel}
+}
+
+// MatchField returns the field among fields whose ID is name, or else the
+// only one labelled name, ignoring case.
+func MatchField(fields []models.Field, name string) (*models.Field, error) {
+	var byLabel []int
+	for i, f := range fields {
+		if f.Id == name {
+			return &fields[i], nil
+		}
+		if strings.EqualFold(f.Label, name) {
+			byLabel = append(byLabel, i)
+		}
+	}
+	switch len(byLabel) {
+	case 0:
+		return nil, &connect.ResolveError{Kind: connect.KindNotFound, Resource: "field", Name: name}
+	case 1:
+		return &fields[byLabel[0]], nil
+	}
+	candidates := make([]connect.Candidate, len(byLabel))
+	for i, idx := range byLabel {
+		candidates[i] = connect.Candidate{ID: fields[idx].Id, Name: fields[idx].Label}
+	}
+	return nil, &connect.ResolveError{Kind: connect.KindAmbiguous, Resource: "field", Name: name, Candidates: candidates}
+}
@@ -0,0 +1,112 @@
+package secretref
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/1password-connect/mcp-server/connect"
+	"github.com/1password-connect/mcp-server/models"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		ref  string
+		want Reference
+		err  string // Substring of the error; empty when ref is valid
+	}{
+		{ref: "op://Production/Database/password", want: Reference{Vault: "Production", Item: "Database", Field: "password", Attribute: AttrValue}},
+		{ref: "op://Production/Database/Admin/pin", want: Reference{Vault: "Production", Item: "Database", Section: "Admin", Field: "pin", Attribute: AttrValue}},
+		{ref: "op://v/i/otp?attribute=otp", want: Reference{Vault: "v", Item: "i", Field: "otp", Attribute: AttrOTP}},
+		{ref: "op://v/i/otp?attribute=TOTP", want: Reference{Vault: "v", Item: "i", Field: "otp", Attribute: AttrOTP}},
+		{ref: "op://v/i/s/f?attribute=purpose", want: Reference{Vault: "v", Item: "i", Section: "s", Field: "f", Attribute: AttrPurpose}},
+
+		{ref: "https://v/i/f", err: "must start with op://"},
+		{ref: "op://v/f", err: "expected op://vault/item/field"},
+		{ref: "op://v/i/s/f/x", err: "expected op://vault/item/field"},
+		{ref: "op://v//f", err: "empty path segment"},
+		{ref: "op://v/i/f/", err: "empty path segment"},
+		{ref: "op://v/i/f?format=json", err: `unsupported query parameter "format"`},
+		{ref: "op://v/i/f?attribute=color", err: `unsupported attribute "color"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			got, err := Parse(tt.ref)
+			switch {
+			case tt.err == "" && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case tt.err == "" && got != tt.want:
+				t.Errorf("Parse = %+v, want %+v", got, tt.want)
+			case tt.err != "" && err == nil:
+				t.Errorf("Parse = %+v, want an error about %q", got, tt.err)
+			case tt.err != "" && !strings.Contains(err.Error(), tt.err):
+				t.Errorf("error = %v, want it to mention %q", err, tt.err)
+			}
+			var syntaxErr *SyntaxError
+			if err != nil && !errors.As(err, &syntaxErr) {
+				t.Errorf("error %T is not a *SyntaxError", err)
+			}
+		})
+	}
+}
+
+func TestSelect(t *testing.T) {
+	item := &models.FullItem{
+		Sections: []map[string]interface{}{
+			{"id": "admin", "label": "Admin"},
+			{"id": "ops1", "label": "Ops"},
+			{"id": "ops2", "label": "ops"},
+		},
+		Fields: []models.Field{
+			{Id: "password", Label: "password", Purpose: "PASSWORD", TypeField: "CONCEALED", Value: "hunter2"},
+			{Id: "otp", Label: "one-time password", TypeField: "TOTP", Value: "otpauth://totp/x", Totp: "123456"},
+			{Id: "pin", Label: "PIN", TypeField: "CONCEALED", Value: "1234", Section: map[string]interface{}{"id": "admin"}},
+			{Id: "key1", Label: "key", TypeField: "STRING", Value: "a"},
+			{Id: "key2", Label: "Key", TypeField: "STRING", Value: "b"},
+		},
+	}
+	tests := []struct {
+		ref  string
+		want string
+		kind connect.ErrorKind // Set when the reference does not resolve to a field
+		err  string            // Substring of the error
+	}{
+		{ref: "op://v/i/password", want: "hunter2"},
+		{ref: "op://v/i/ONE-TIME PASSWORD?attribute=otp", want: "123456"},
+		{ref: "op://v/i/admin/pin", want: "1234"},
+		{ref: "op://v/i/Admin/pin?attribute=type", want: "CONCEALED"},
+		{ref: "op://v/i/key1", want: "a"},
+
+		{ref: "op://v/i/password?attribute=otp", err: "has no one-time password"},
+		{ref: "op://v/i/missing", kind: connect.KindNotFound, err: `no field named "missing"`},
+		{ref: "op://v/i/Admin/password", kind: connect.KindNotFound, err: `no field named "password"`},
+		{ref: "op://v/i/Staging/pin", kind: connect.KindNotFound, err: `no section named "Staging"`},
+		{ref: "op://v/i/key", kind: connect.KindAmbiguous, err: `field name "key" is ambiguous`},
+		{ref: "op://v/i/OPS/pin", kind: connect.KindAmbiguous, err: `section name "OPS" is ambiguous`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			ref, err := Parse(tt.ref)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := ref.Select(item)
+			if tt.err == "" {
+				if err != nil || got != tt.want {
+					t.Errorf("Select = %q, %v; want %q", got, err, tt.want)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Fatalf("error = %v, want it to mention %q", err, tt.err)
+			}
+			var resolveErr *connect.ResolveError
+			if tt.kind != "" && (!errors.As(err, &resolveErr) || resolveErr.Kind != tt.kind) {
+				t.Errorf("error = %#v, want a %s ResolveError", err, tt.kind)
+			}
+			if tt.kind == connect.KindAmbiguous && len(resolveErr.Candidates) != 2 {
+				t.Errorf("candidates = %v, want both matches", resolveErr.Candidates)
+			}
+		})
+	}
+}
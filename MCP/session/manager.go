@@ -0,0 +1,133 @@
+// Package session tracks Streamable HTTP sessions and the Connect credentials
+// each one was initialized with.
+package session
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/1password-connect/mcp-server/config"
+	"github.com/google/uuid"
+)
+
+const idPrefix = "mcp-session-"
+
+type entry struct {
+	cfg        *config.APIConfig
+	lastSeen   time.Time
+	terminated bool
+}
+
+// Manager implements server.SessionIdManager. Sessions expire after being
+// idle for longer than the configured TTL; clients then receive 404 and are
+// expected to initialize a new session.
+type Manager struct {
+	mu       sync.Mutex
+	sessions map[string]*entry
+	ttl      time.Duration
+	now      func() time.Time // The clock sessions age by; tests replace it
+}
+
+func NewManager(ttl time.Duration) *Manager {
+	if ttl <= 0 {
+		ttl = config.DefaultSessionTTL
+	}
+	return &Manager{
+		sessions: make(map[string]*entry),
+		ttl:      ttl,
+		now:      time.Now,
+	}
+}
+
+func (m *Manager) Generate() string {
+	id := idPrefix + uuid.New().String()
+	m.mu.Lock()
+	m.sessions[id] = &entry{lastSeen: m.now()}
+	m.mu.Unlock()
+	return id
+}
+
+// Validate reports unknown and expired sessions as terminated so that clients
+// holding an ID from before a restart or an idle timeout re-initialize.
+func (m *Manager) Validate(sessionID string) (isTerminated bool, err error) {
+	if !strings.HasPrefix(sessionID, idPrefix) {
+		return false, fmt.Errorf("invalid session id: %q", sessionID)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.sessions[sessionID]
+	if !ok || e.terminated {
+		return true, nil
+	}
+	if m.now().Sub(e.lastSeen) > m.ttl {
+		e.terminated = true
+		return true, nil
+	}
+	e.lastSeen = m.now()
+	return false, nil
+}
+
+func (m *Manager) Terminate(sessionID string) (isNotAllowed bool, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if e, ok := m.sessions[sessionID]; ok {
+		e.terminated = true
+		e.cfg = nil
+	}
+	return false, nil
+}
+
+// Active reports whether sessionID belongs to a live session.
+func (m *Manager) Active(sessionID string) bool {
+	isTerminated, err := m.Validate(sessionID)
+	return err == nil && !isTerminated
+}
+
+// Bind associates the credentials supplied at initialize time with a session.
+// Later requests in the session use them regardless of their own headers.
+func (m *Manager) Bind(sessionID string, cfg *config.APIConfig) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if e, ok := m.sessions[sessionID]; ok && !e.terminated {
+		e.cfg = cfg
+	}
+}
+
+// Config returns the credentials bound to a live session.
+func (m *Manager) Config(sessionID string) (*config.APIConfig, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.sessions[sessionID]
+	if !ok || e.terminated || e.cfg == nil {
+		return nil, false
+	}
+	return e.cfg, true
+}
+
+// Run removes terminated and expired sessions every interval until ctx is
+// done. Forgotten IDs are still answered with 404 by Validate.
+func (m *Manager) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.sweep()
+		}
+	}
+}
+
+func (m *Manager) sweep() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, e := range m.sessions {
+		if e.terminated || m.now().Sub(e.lastSeen) > m.ttl {
+			delete(m.sessions, id)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package session
+
+import (
+	"testing"
+	"time"
+
+	"github.com/1password-connect/mcp-server/config"
+)
+
+// clock is a manual clock for a Manager.
+type clock struct{ t time.Time }
+
+func (c *clock) now() time.Time { return c.t }
+
+func (c *clock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func newTestManager(ttl time.Duration) (*Manager, *clock) {
+	c := &clock{t: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	m := NewManager(ttl)
+	m.now = c.now
+	return m, c
+}
+
+func TestValidate(t *testing.T) {
+	m, c := newTestManager(time.Minute)
+	id := m.Generate()
+
+	if terminated, err := m.Validate("not-a-session"); err == nil || terminated {
+		t.Errorf("Validate(foreign ID) = %v, %v; want an error", terminated, err)
+	}
+	if terminated, err := m.Validate(idPrefix + "unknown"); err != nil || !terminated {
+		t.Errorf("Validate(unknown ID) = %v, %v; want terminated", terminated, err)
+	}
+
+	// Each request within the TTL keeps the session alive.
+	for range 3 {
+		c.advance(50 * time.Second)
+		if !m.Active(id) {
+			t.Fatal("session expired while in use")
+		}
+	}
+	c.advance(time.Minute + time.Second)
+	if terminated, err := m.Validate(id); err != nil || !terminated {
+		t.Errorf("Validate(idle session) = %v, %v; want terminated", terminated, err)
+	}
+	// An expired session stays expired.
+	c.advance(-time.Hour)
+	if m.Active(id) {
+		t.Error("expired session became active again")
+	}
+}
+
+func TestTerminate(t *testing.T) {
+	m, _ := newTestManager(time.Minute)
+	id := m.Generate()
+	m.Bind(id, &config.APIConfig{BaseURL: "http://connect"})
+
+	if notAllowed, err := m.Terminate(id); notAllowed || err != nil {
+		t.Fatalf("Terminate = %v, %v", notAllowed, err)
+	}
+	if m.Active(id) {
+		t.Error("terminated session is active")
+	}
+	if cfg, ok := m.Config(id); ok {
+		t.Errorf("terminated session has credentials %+v", cfg)
+	}
+	m.Bind(id, &config.APIConfig{BaseURL: "http://other"})
+	if _, ok := m.Config(id); ok {
+		t.Error("credentials bound to a terminated session")
+	}
+}
+
+func TestBind(t *testing.T) {
+	m, c := newTestManager(time.Minute)
+	id := m.Generate()
+	if _, ok := m.Config(id); ok {
+		t.Error("new session has credentials before Bind")
+	}
+	bound := &config.APIConfig{BaseURL: "http://connect"}
+	m.Bind(id, bound)
+	if cfg, ok := m.Config(id); !ok || cfg != bound {
+		t.Errorf("Config = %+v, %v; want the bound credentials", cfg, ok)
+	}
+	m.Bind(idPrefix+"unknown", bound)
+	if _, ok := m.Config(idPrefix + "unknown"); ok {
+		t.Error("Bind created a session")
+	}
+
+	c.advance(2 * time.Minute)
+	m.Validate(id)
+	if _, ok := m.Config(id); ok {
+		t.Error("expired session still has credentials")
+	}
+}
+
+func TestSweep(t *testing.T) {
+	m, c := newTestManager(time.Minute)
+	idle := m.Generate()
+	terminated := m.Generate()
+	m.Terminate(terminated)
+	c.advance(40 * time.Second)
+	active := m.Generate()
+	c.advance(30 * time.Second)
+
+	m.sweep()
+	if _, ok := m.sessions[idle]; ok {
+		t.Error("sweep kept the idle session")
+	}
+	if _, ok := m.sessions[terminated]; ok {
+		t.Error("sweep kept the terminated session")
+	}
+	if !m.Active(active) {
+		t.Error("sweep removed the active session")
+	}
+	// Forgotten sessions are still refused.
+	if isTerminated, err := m.Validate(idle); err != nil || !isTerminated {
+		t.Errorf("Validate(swept session) = %v, %v; want terminated", isTerminated, err)
+	}
+}
+
+func TestNewManagerDefaultTTL(t *testing.T) {
+	if m := NewManager(0); m.ttl != config.DefaultSessionTTL {
+		t.Errorf("ttl = %v, want %v", m.ttl, config.DefaultSessionTTL)
+	}
+}
@@ -2,80 +2,41 @@ package tools
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"strings"
 
 	"github.com/1password-connect/mcp-server/config"
+	"github.com/1password-connect/mcp-server/connect"
 	"github.com/1password-connect/mcp-server/models"
+	"github.com/1password-connect/mcp-server/tools/common"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
 func GetapiactivityHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	defaultClient := connect.NewClient(cfg)
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		client := connect.ClientFor(ctx, defaultClient)
 		args, ok := request.Params.Arguments.(map[string]any)
 		if !ok {
 			return mcp.NewToolResultError("Invalid arguments object"), nil
 		}
-		queryParams := make([]string, 0)
-		if val, ok := args["limit"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("limit=%v", val))
-		}
-		if val, ok := args["offset"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("offset=%v", val))
-		}
-		queryString := ""
-		if len(queryParams) > 0 {
-			queryString = "?" + strings.Join(queryParams, "&")
-		}
-		url := fmt.Sprintf("%s/activity%s", cfg.BaseURL, queryString)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// Set authentication based on auth type
-		if cfg.BearerToken != "" {
-			req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", cfg.BearerToken))
-		}
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
+		params, err := parseGetApiActivityParams(args)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
-
-		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
+		var result []models.APIRequest
+		if _, err := client.Get(ctx, connect.Path("activity"), params.query(), &result); err != nil {
+			return common.ErrorResult(err), nil
 		}
-		// Use properly typed response
-		var result []APIRequest
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
-		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
+		result, err = client.FilterActivity(ctx, result)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
+			return common.ErrorResult(err), nil
 		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		return common.ListResult("requests", result), nil
 	}
 }
 
 func CreateGetapiactivityTool(cfg *config.APIConfig) models.Tool {
-	tool := mcp.NewTool("get_activity",
-		mcp.WithDescription("Retrieve a list of API Requests that have been made."),
-		mcp.WithNumber("limit", mcp.Description("How many API Events should be retrieved in a single request.")),
-		mcp.WithNumber("offset", mcp.Description("How far into the collection of API Events should the response start")),
+	tool := getApiActivityTool(
+		common.WithListOutputSchema[models.APIRequest]("requests"),
 	)
 
 	return models.Tool{
@@ -0,0 +1,52 @@
+// Code generated by openapigen from openapi.yaml. DO NOT EDIT.
+
+package tools
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/1password-connect/mcp-server/tools/common"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// getApiActivityParams holds the arguments of the get_activity tool.
+type getApiActivityParams struct {
+	Limit  *int // How many API Events should be retrieved in a single request.
+	Offset *int // How far into the collection of API Events should the response start
+}
+
+// parseGetApiActivityParams reads the arguments of the get_activity tool.
+func parseGetApiActivityParams(args map[string]any) (getApiActivityParams, error) {
+	var p getApiActivityParams
+	var err error
+	if p.Limit, err = common.QueryInt(args, "limit"); err != nil {
+		return p, err
+	}
+	if p.Offset, err = common.QueryInt(args, "offset"); err != nil {
+		return p, err
+	}
+	return p, nil
+}
+
+// query returns the query parameters that are set.
+func (p getApiActivityParams) query() url.Values {
+	query := url.Values{}
+	if p.Limit != nil {
+		query.Set("limit", strconv.Itoa(*p.Limit))
+	}
+	if p.Offset != nil {
+		query.Set("offset", strconv.Itoa(*p.Offset))
+	}
+	return query
+}
+
+// getApiActivityTool returns the definition of the get_activity tool, followed by opts.
+func getApiActivityTool(opts ...mcp.ToolOption) mcp.Tool {
+	return common.NewTool("get_activity", "List API activity", http.MethodGet, append([]mcp.ToolOption{
+		mcp.WithDescription("Retrieve a list of API Requests that have been made."),
+		mcp.WithInteger("limit", mcp.Description("How many API Events should be retrieved in a single request."), mcp.DefaultNumber(50)),
+		mcp.WithInteger("offset", mcp.Description("How far into the collection of API Events should the response start"), mcp.DefaultNumber(0)),
+	}, opts...)...)
+}
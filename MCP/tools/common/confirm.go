@@ -0,0 +1,89 @@
+package common
+
+import (
+	"context"
+	"errors"
+
+	"github.com/1password-connect/mcp-server/config"
+	"github.com/1password-connect/mcp-server/connect"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Error kinds for destructive calls that were not confirmed.
+const (
+	KindConfirmationRequired connect.ErrorKind = "confirmation_required"
+	KindDeclined             connect.ErrorKind = "declined"
+)
+
+// WithConfirm adds the confirm argument to destructive tools when the server
+// asks for confirmation, for clients that cannot be asked directly.
+func WithConfirm(cfg *config.APIConfig) mcp.ToolOption {
+	return func(tool *mcp.Tool) {
+		if cfg.ConfirmDestructive {
+			mcp.WithBoolean("confirm", mcp.Description("Confirm the operation. Only needed when the client does not support elicitation; otherwise the user is asked directly"))(tool)
+		}
+	}
+}
+
+// Confirm asks the user to approve the operation described by message. It
+// returns nil when the call may proceed, and otherwise the result to return
+// instead. Clients that support elicitation are asked directly, and a confirm
+// argument does not bypass that; other clients must pass confirm: true.
+func Confirm(ctx context.Context, args map[string]any, message string) *mcp.CallToolResult {
+	srv := server.ServerFromContext(ctx)
+	if srv == nil || !supportsElicitation(ctx) {
+		if confirmed, _ := args["confirm"].(bool); confirmed {
+			return nil
+		}
+		return toolError(message+" Pass confirm: true to proceed.", ToolError{
+			Kind:    KindConfirmationRequired,
+			Message: message,
+			Hint:    "Show the operation to the user and call again with confirm: true once they agree",
+		})
+	}
+
+	result, err := srv.RequestElicitation(ctx, mcp.ElicitationRequest{
+		Params: mcp.ElicitationParams{
+			Message: message,
+			RequestedSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"confirm": map[string]any{
+						"type":        "boolean",
+						"title":       "Confirm",
+						"description": "Carry out the operation",
+					},
+				},
+				"required": []string{"confirm"},
+			},
+		},
+	})
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return ErrorResult(err)
+		}
+		return toolError("Failed to ask the user for confirmation: "+err.Error(), ToolError{Kind: connect.KindUnknown, Message: err.Error()})
+	}
+	if result.Action == mcp.ElicitationResponseActionAccept {
+		if content, ok := result.Content.(map[string]any); ok && content["confirm"] == true {
+			return nil
+		}
+	}
+	return toolError("The user did not confirm the operation", ToolError{
+		Kind:    KindDeclined,
+		Message: message,
+		Hint:    "The user chose not to proceed; do not retry without asking them",
+	})
+}
+
+// supportsElicitation reports whether the client of the current session
+// declared the elicitation capability.
+func supportsElicitation(ctx context.Context) bool {
+	session, ok := server.ClientSessionFromContext(ctx).(server.SessionWithClientInfo)
+	if !ok {
+		return false
+	}
+	_, canElicit := session.(server.SessionWithElicitation)
+	return canElicit && session.GetClientCapabilities().Elicitation != nil
+}
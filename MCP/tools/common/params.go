@@ -0,0 +1,113 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// The helpers below read tool arguments for the parameter parsers generated
+// from openapi.yaml.
+
+// PathParam returns the required path parameter name.
+func PathParam(args map[string]any, name string) (string, error) {
+	val, ok := args[name]
+	if !ok {
+		return "", fmt.Errorf("Missing required path parameter: %s", name)
+	}
+	s, ok := val.(string)
+	if !ok {
+		return "", fmt.Errorf("Invalid path parameter: %s", name)
+	}
+	return s, nil
+}
+
+// QueryString returns the optional query parameter name, or nil when it is
+// absent.
+func QueryString(args map[string]any, name string) (*string, error) {
+	val, ok := args[name]
+	if !ok || val == nil {
+		return nil, nil
+	}
+	s, ok := val.(string)
+	if !ok {
+		return nil, fmt.Errorf("Invalid query parameter: %s must be a string", name)
+	}
+	return &s, nil
+}
+
+// QueryInt returns the optional integer query parameter name, or nil when it
+// is absent. JSON numbers arrive as float64, so whole floats and numeric
+// strings are accepted too.
+func QueryInt(args map[string]any, name string) (*int, error) {
+	val, ok := args[name]
+	if !ok || val == nil {
+		return nil, nil
+	}
+	var n int
+	switch v := val.(type) {
+	case float64:
+		if v != math.Trunc(v) || math.Abs(v) > math.MaxInt32 {
+			return nil, fmt.Errorf("Invalid query parameter: %s must be an integer", name)
+		}
+		n = int(v)
+	case int:
+		n = v
+	case string:
+		parsed, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid query parameter: %s must be an integer", name)
+		}
+		n = parsed
+	default:
+		return nil, fmt.Errorf("Invalid query parameter: %s must be an integer", name)
+	}
+	return &n, nil
+}
+
+// QueryBool returns the optional boolean query parameter name, or nil when
+// it is absent. Strings such as "true" are accepted too.
+func QueryBool(args map[string]any, name string) (*bool, error) {
+	val, ok := args[name]
+	if !ok || val == nil {
+		return nil, nil
+	}
+	switch v := val.(type) {
+	case bool:
+		return &v, nil
+	case string:
+		b, err := strconv.ParseBool(v)
+		if err == nil {
+			return &b, nil
+		}
+	}
+	return nil, fmt.Errorf("Invalid query parameter: %s must be a boolean", name)
+}
+
+// DecodeBody decodes the arguments into a request body. Arguments that are
+// not part of the body, such as path parameters, are ignored.
+func DecodeBody(args map[string]any, body any) error {
+	return decode(args, body)
+}
+
+// DecodeBodyArg decodes the required argument name into a request body, for
+// bodies that are not objects and so cannot be the arguments themselves.
+func DecodeBodyArg(args map[string]any, name string, body any) error {
+	val, ok := args[name]
+	if !ok {
+		return fmt.Errorf("Missing required body parameter: %s", name)
+	}
+	return decode(val, body)
+}
+
+func decode(val any, body any) error {
+	data, err := json.Marshal(val)
+	if err != nil {
+		return fmt.Errorf("Failed to marshal arguments: %v", err)
+	}
+	if err := json.Unmarshal(data, body); err != nil {
+		return fmt.Errorf("Failed to convert arguments to request type: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+// Package common holds helpers shared by the tool packages under tools/.
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+
+	"github.com/1password-connect/mcp-server/config"
+	"github.com/1password-connect/mcp-server/connect"
+	"github.com/1password-connect/mcp-server/policy"
+	"github.com/1password-connect/mcp-server/redact"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Error kinds for failures that happen before the Connect server answers.
+const (
+	KindCancelled connect.ErrorKind = "cancelled"
+	KindTimeout   connect.ErrorKind = "timeout"
+	KindNetwork   connect.ErrorKind = "network_error"
+	KindPolicy    connect.ErrorKind = "policy_denied"
+	KindRedacted  connect.ErrorKind = "redacted"
+)
+
+// ToolError is the structured content of a failed tool call, so that agents
+// can branch on Kind instead of parsing the text.
+type ToolError struct {
+	Kind      connect.ErrorKind `json:"kind"`
+	Status    int               `json:"status,omitempty"`
+	Message   string            `json:"message"`
+	Hint      string            `json:"hint,omitempty"`
+	RequestID string            `json:"requestId,omitempty"`
+	Attempts  int               `json:"attempts,omitempty"`
+
+	Candidates []connect.Candidate `json:"candidates,omitempty"`
+}
+
+// JSONResult returns the object v as the structured content of a tool result,
+// pretty-printed as its text for clients that do not read structured content.
+func JSONResult(v any) *mcp.CallToolResult {
+	prettyJSON, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to format JSON", err)
+	}
+	return mcp.NewToolResultStructured(v, string(prettyJSON))
+}
+
+// ListResult returns a list as a tool result. Structured content must be an
+// object, so the list is wrapped under key there; the text is the bare list
+// as before.
+func ListResult[T any](key string, items []T) *mcp.CallToolResult {
+	if items == nil {
+		items = []T{}
+	}
+	prettyJSON, err := json.MarshalIndent(items, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("Failed to format JSON", err)
+	}
+	return mcp.NewToolResultStructured(map[string]any{key: items}, string(prettyJSON))
+}
+
+// ErrorResult converts an error returned by the connect client into a tool
+// result whose structured content classifies the failure.
+func ErrorResult(err error) *mcp.CallToolResult {
+	var apiErr *connect.APIError
+	var decodeErr *connect.DecodeError
+	var resolveErr *connect.ResolveError
+	var readOnlyErr *connect.ReadOnlyError
+	var vaultErr *policy.VaultError
+	var sizeErr *connect.SizeError
+	var redactErr *redact.Error
+	switch {
+	case errors.As(err, &apiErr):
+		return toolError(apiErr.Error(), ToolError{
+			Kind:      apiErr.Kind,
+			Status:    apiErr.StatusCode,
+			Message:   apiErr.Message,
+			Hint:      apiErr.Hint(),
+			RequestID: apiErr.RequestID,
+			Attempts:  connect.Attempts(err),
+		})
+	case errors.As(err, &decodeErr):
+		// The body is left out: the redaction policy never saw it.
+		return toolError(decodeErr.Error(), ToolError{
+			Kind:    connect.KindDecode,
+			Message: decodeErr.Error(),
+			Hint:    "The Connect server answered with a response this server does not understand; check that it runs a supported version",
+		})
+	case errors.As(err, &resolveErr):
+		return toolError(resolveErr.Error(), ToolError{
+			Kind:       resolveErr.Kind,
+			Message:    resolveErr.Error(),
+			Hint:       resolveErr.Hint(),
+			Candidates: resolveErr.Candidates,
+		})
+	case errors.As(err, &readOnlyErr):
+		return toolError(readOnlyErr.Error(), ToolError{
+			Kind:    connect.KindReadOnly,
+			Message: readOnlyErr.Error(),
+			Hint:    "Writes are disabled on this server; ask its operator to unset READ_ONLY",
+		})
+	case errors.As(err, &vaultErr):
+		return toolError(vaultErr.Error(), ToolError{
+			Kind:    KindPolicy,
+			Message: vaultErr.Error(),
+			Hint:    "Use a vault within the scope of the server's policy; list the allowed vaults with get_vaults",
+		})
+	case errors.As(err, &sizeErr):
+		return toolError(sizeErr.Error(), ToolError{
+			Kind:    connect.KindTooLarge,
+			Message: sizeErr.Error(),
+			Hint:    "The server's MAX_DOWNLOAD_SIZE limits downloads; ask its operator to raise it",
+		})
+	case errors.As(err, &redactErr):
+		hint := "The server's REDACTION_MODE hides this value from every call"
+		if redactErr.Mode == config.RedactReveal {
+			hint = "Call again with reveal: true if the user needs to see it"
+		}
+		return toolError(redactErr.Error(), ToolError{Kind: KindRedacted, Message: redactErr.Error(), Hint: hint})
+	case errors.Is(err, context.Canceled):
+		return toolError("Request cancelled", ToolError{Kind: KindCancelled, Message: err.Error()})
+	case errors.Is(err, context.DeadlineExceeded):
+		return toolError("Request timed out", ToolError{Kind: KindTimeout, Message: err.Error()})
+	}
+	kind := connect.KindUnknown
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		kind = KindNetwork
+	}
+	return toolError(err.Error(), ToolError{Kind: kind, Message: err.Error(), Attempts: connect.Attempts(err)})
+}
+
+func toolError(text string, detail ToolError) *mcp.CallToolResult {
+	result := mcp.NewToolResultError(text)
+	result.StructuredContent = map[string]any{"error": detail}
+	return result
+}
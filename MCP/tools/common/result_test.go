@@ -0,0 +1,31 @@
+package common
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/1password-connect/mcp-server/connect"
+)
+
+// TestErrorResultDecodeError checks that a response that could not be
+// decoded, and so was never redacted, does not reach the caller.
+func TestErrorResultDecodeError(t *testing.T) {
+	err := &connect.DecodeError{
+		Body: []byte(`{"fields":[{"id":"password","value":"hunter2"}]`),
+		Err:  errors.New("unexpected end of JSON input"),
+	}
+	result := ErrorResult(err)
+	detail, _ := result.StructuredContent.(map[string]any)["error"].(ToolError)
+	if !result.IsError || detail.Kind != connect.KindDecode {
+		t.Errorf("result = %+v, want a %s error", result, connect.KindDecode)
+	}
+	data, marshalErr := json.Marshal(result)
+	if marshalErr != nil {
+		t.Fatal(marshalErr)
+	}
+	if strings.Contains(string(data), "hunter2") {
+		t.Errorf("result %s shows the response body", data)
+	}
+}
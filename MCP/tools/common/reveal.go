@@ -0,0 +1,15 @@
+package common
+
+import "github.com/mark3labs/mcp-go/mcp"
+
+// WithReveal adds the reveal argument to tools whose output can contain
+// secrets that the redaction policy masks.
+func WithReveal() mcp.ToolOption {
+	return mcp.WithBoolean("reveal", mcp.Description("Return secret values instead of masking them. Only honoured when the server runs with REDACTION_MODE=reveal"))
+}
+
+// Reveal reports whether the call asked for secret values with reveal: true.
+func Reveal(args map[string]any) bool {
+	reveal, _ := args["reveal"].(bool)
+	return reveal
+}
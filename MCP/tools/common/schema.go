@@ -0,0 +1,115 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// WithBody adds the properties of a request body's JSON object schema, such
+// as one generated from openapi.yaml, as arguments. Required properties stay
+// required unless they are listed in filled, which the handler supplies
+// itself, for example from a path parameter.
+func WithBody(schema string, filled ...string) mcp.ToolOption {
+	var body struct {
+		Properties map[string]any `json:"properties"`
+		Required   []string       `json:"required"`
+	}
+	if err := json.Unmarshal([]byte(schema), &body); err != nil {
+		panic(fmt.Sprintf("invalid request body schema: %v", err))
+	}
+	return func(t *mcp.Tool) {
+		if t.InputSchema.Properties == nil {
+			t.InputSchema.Properties = make(map[string]any)
+		}
+		maps.Copy(t.InputSchema.Properties, body.Properties)
+		for _, name := range body.Required {
+			if !slices.Contains(filled, name) {
+				t.InputSchema.Required = append(t.InputSchema.Required, name)
+			}
+		}
+	}
+}
+
+// WithBodyArg adds a request body's JSON schema as the required argument
+// name. It is used for bodies that are not objects, such as arrays, since the
+// arguments themselves must be an object.
+func WithBodyArg(name, schema string) mcp.ToolOption {
+	var body map[string]any
+	if err := json.Unmarshal([]byte(schema), &body); err != nil {
+		panic(fmt.Sprintf("invalid request body schema: %v", err))
+	}
+	return func(t *mcp.Tool) {
+		if t.InputSchema.Properties == nil {
+			t.InputSchema.Properties = make(map[string]any)
+		}
+		t.InputSchema.Properties[name] = body
+		t.InputSchema.Required = append(t.InputSchema.Required, name)
+	}
+}
+
+// WithOutputSchema declares the structured content of a tool whose result is
+// a T, as returned by JSONResult.
+func WithOutputSchema[T any]() mcp.ToolOption {
+	return withSchema(schemaFor[T]())
+}
+
+// WithListOutputSchema declares the structured content of a tool whose result
+// is a list of T, as returned by ListResult under key.
+func WithListOutputSchema[T any](key string) mcp.ToolOption {
+	items := schemaFor[T]()
+	items.Types = nil
+	items.Type = "object"
+	return withSchema(&jsonschema.Schema{
+		Type:       "object",
+		Properties: map[string]*jsonschema.Schema{key: {Type: "array", Items: items}},
+		Required:   []string{key},
+	})
+}
+
+func withSchema(schema *jsonschema.Schema) mcp.ToolOption {
+	raw, err := json.Marshal(schema)
+	if err != nil {
+		panic(fmt.Sprintf("failed to encode output schema: %v", err))
+	}
+	return mcp.WithRawOutputSchema(raw)
+}
+
+// schemaFor derives a schema from the JSON encoding of T. The models mirror
+// what Connect documents, but servers add properties and leave out others,
+// so the schema only describes the shape of the known properties: nothing is
+// required, unknown properties are allowed, and nested objects and lists may
+// be null.
+func schemaFor[T any]() *jsonschema.Schema {
+	schema, err := jsonschema.For[T](&jsonschema.ForOptions{IgnoreInvalidTypes: true})
+	if err != nil {
+		panic(fmt.Sprintf("failed to derive output schema: %v", err))
+	}
+	loosen(schema, true)
+	return schema
+}
+
+func loosen(schema *jsonschema.Schema, root bool) {
+	if schema == nil {
+		return
+	}
+	if schema.Properties != nil {
+		schema.Required = nil
+		schema.AdditionalProperties = nil
+	}
+	if !root && (schema.Type == "object" || schema.Type == "array") {
+		schema.Types = []string{"null", schema.Type}
+		schema.Type = ""
+	}
+	for _, property := range schema.Properties {
+		loosen(property, false)
+	}
+	loosen(schema.Items, false)
+	if schema.AdditionalProperties != nil {
+		loosen(schema.AdditionalProperties, false)
+	}
+}
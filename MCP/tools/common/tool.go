@@ -0,0 +1,31 @@
+package common
+
+import (
+	"net/http"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// NewTool builds the definition of a tool whose Connect requests use method,
+// with a human title and the behaviour hints that method implies:
+//   - GET tools only read.
+//   - POST creates a new item on every call.
+//   - PUT and DELETE overwrite or remove data, but repeating them changes
+//     nothing further.
+//   - PATCH modifies data and may not be safe to repeat.
+//
+// Tools only reach the configured Connect server, so none is open-world.
+func NewTool(name, title, method string, opts ...mcp.ToolOption) mcp.Tool {
+	readOnly := method == http.MethodGet || method == http.MethodHead
+	destructive := method == http.MethodPut || method == http.MethodPatch || method == http.MethodDelete
+	idempotent := method != http.MethodPost && method != http.MethodPatch
+
+	opts = append(opts, mcp.WithToolAnnotation(mcp.ToolAnnotation{
+		Title:           title,
+		ReadOnlyHint:    mcp.ToBoolPtr(readOnly),
+		DestructiveHint: mcp.ToBoolPtr(destructive),
+		IdempotentHint:  mcp.ToBoolPtr(idempotent),
+		OpenWorldHint:   mcp.ToBoolPtr(false),
+	}))
+	return mcp.NewTool(name, opts...)
+}
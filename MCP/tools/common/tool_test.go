@@ -0,0 +1,30 @@
+package common
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestNewTool(t *testing.T) {
+	tests := []struct {
+		method                            string
+		readOnly, destructive, idempotent bool
+	}{
+		{method: http.MethodGet, readOnly: true, idempotent: true},
+		{method: http.MethodHead, readOnly: true, idempotent: true},
+		{method: http.MethodPost},
+		{method: http.MethodPut, destructive: true, idempotent: true},
+		{method: http.MethodPatch, destructive: true},
+		{method: http.MethodDelete, destructive: true, idempotent: true},
+	}
+	for _, tt := range tests {
+		a := NewTool("tool", "Tool", tt.method).Annotations
+		if a.Title != "Tool" {
+			t.Errorf("%s: title = %q", tt.method, a.Title)
+		}
+		if *a.ReadOnlyHint != tt.readOnly || *a.DestructiveHint != tt.destructive || *a.IdempotentHint != tt.idempotent || *a.OpenWorldHint {
+			t.Errorf("%s: readOnly=%v destructive=%v idempotent=%v openWorld=%v; want %v %v %v false", tt.method,
+				*a.ReadOnlyHint, *a.DestructiveHint, *a.IdempotentHint, *a.OpenWorldHint, tt.readOnly, tt.destructive, tt.idempotent)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package tools
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/1password-connect/mcp-server/config"
+	"github.com/1password-connect/mcp-server/connect"
+	"github.com/1password-connect/mcp-server/models"
+	"github.com/1password-connect/mcp-server/redact"
+	"github.com/1password-connect/mcp-server/tools/common"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func DownloadfilebyidHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	defaultClient := connect.NewClient(cfg)
+	policy := redact.New(cfg)
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		client := connect.ClientFor(ctx, defaultClient)
+		args, ok := request.Params.Arguments.(map[string]any)
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments object"), nil
+		}
+		params, err := parseDownloadFileByIDParams(args)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		vaultUuid, err := client.ResolveVault(ctx, params.VaultUuid)
+		if err != nil {
+			return common.ErrorResult(err), nil
+		}
+		itemUuid, err := client.ResolveItem(ctx, vaultUuid, params.ItemUuid)
+		if err != nil {
+			return common.ErrorResult(err), nil
+		}
+		if policy.MasksFiles(common.Reveal(args)) {
+			return common.ErrorResult(&redact.Error{What: "File content", Mode: policy.Mode}), nil
+		}
+		maxSize := cfg.MaxDownloadSize
+		if maxSize <= 0 {
+			maxSize = config.DefaultMaxDownloadSize
+		}
+
+		contentPath := connect.Path("vaults", vaultUuid, "items", itemUuid, "files", params.FileUuid, "content")
+		resp, err := client.Stream(ctx, contentPath, nil, "application/octet-stream, application/json")
+		if err != nil {
+			return common.ErrorResult(err), nil
+		}
+		defer resp.Body.Close()
+
+		// Refuse early when the server announces a body we are not willing to hold.
+		if cl := resp.Header.Get("Content-Length"); cl != "" {
+			if n, err := strconv.ParseInt(cl, 10, 64); err == nil && n > maxSize {
+				return common.ErrorResult(&connect.SizeError{Path: contentPath, Size: n, Limit: maxSize}), nil
+			}
+		}
+
+		// Read at most one byte past the limit so oversized bodies without a
+		// Content-Length are detected without buffering them in full.
+		content, err := io.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+		if err != nil {
+			return common.ErrorResult(fmt.Errorf("failed to read response body: %w", err)), nil
+		}
+		if int64(len(content)) > maxSize {
+			return common.ErrorResult(&connect.SizeError{Path: contentPath, Limit: maxSize}), nil
+		}
+
+		filename := params.FileUuid
+		if _, disposition, err := mime.ParseMediaType(resp.Header.Get("Content-Disposition")); err == nil && disposition["filename"] != "" {
+			filename = disposition["filename"]
+		}
+		mimeType := detectMIMEType(filename, content)
+
+		if isTextContent(mimeType, content) {
+			return mcp.NewToolResultText(string(content)), nil
+		}
+
+		uri := fmt.Sprintf("onepassword://vaults/%s/items/%s/files/%s/content", vaultUuid, itemUuid, params.FileUuid)
+		summary := fmt.Sprintf("Binary file %q (%s, %d bytes)", filename, mimeType, len(content))
+		return mcp.NewToolResultResource(summary, mcp.BlobResourceContents{
+			URI:      uri,
+			MIMEType: mimeType,
+			Blob:     base64.StdEncoding.EncodeToString(content),
+		}), nil
+	}
+}
+
+// detectMIMEType prefers the type implied by the file extension and falls
+// back to sniffing the content, since Connect always answers with
+// application/octet-stream.
+func detectMIMEType(filename string, content []byte) string {
+	if t := mime.TypeByExtension(path.Ext(filename)); t != "" {
+		return t
+	}
+	return http.DetectContentType(content)
+}
+
+func isTextContent(mimeType string, content []byte) bool {
+	if !utf8.Valid(content) {
+		return false
+	}
+	mediaType, _, err := mime.ParseMediaType(mimeType)
+	if err != nil {
+		return false
+	}
+	switch {
+	case strings.HasPrefix(mediaType, "text/"):
+		return true
+	case mediaType == "application/json", mediaType == "application/xml",
+		mediaType == "application/x-pem-file", mediaType == "application/x-yaml":
+		return true
+	}
+	return false
+}
+
+func CreateDownloadfilebyidTool(cfg *config.APIConfig) models.Tool {
+	tool := downloadFileByIDTool(
+		common.WithReveal(),
+	)
+
+	return models.Tool{
+		Definition: tool,
+		Handler:    DownloadfilebyidHandler(cfg),
+		Timeout:    2 * time.Minute,
+	}
+}
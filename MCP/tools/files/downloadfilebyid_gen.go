@@ -0,0 +1,43 @@
+// Code generated by openapigen from openapi.yaml. DO NOT EDIT.
+
+package tools
+
+import (
+	"net/http"
+
+	"github.com/1password-connect/mcp-server/tools/common"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// downloadFileByIDParams holds the arguments of the get_vaults_vaultUuid_items_itemUuid_files_fileUuid_content tool.
+type downloadFileByIDParams struct {
+	VaultUuid string // The UUID or name of the Vault the item is in
+	ItemUuid  string // The UUID or name of the Item the File is in
+	FileUuid  string // UUID of the file to get content from
+}
+
+// parseDownloadFileByIDParams reads the arguments of the get_vaults_vaultUuid_items_itemUuid_files_fileUuid_content tool.
+func parseDownloadFileByIDParams(args map[string]any) (downloadFileByIDParams, error) {
+	var p downloadFileByIDParams
+	var err error
+	if p.VaultUuid, err = common.PathParam(args, "vaultUuid"); err != nil {
+		return p, err
+	}
+	if p.ItemUuid, err = common.PathParam(args, "itemUuid"); err != nil {
+		return p, err
+	}
+	if p.FileUuid, err = common.PathParam(args, "fileUuid"); err != nil {
+		return p, err
+	}
+	return p, nil
+}
+
+// downloadFileByIDTool returns the definition of the get_vaults_vaultUuid_items_itemUuid_files_fileUuid_content tool, followed by opts.
+func downloadFileByIDTool(opts ...mcp.ToolOption) mcp.Tool {
+	return common.NewTool("get_vaults_vaultUuid_items_itemUuid_files_fileUuid_content", "Download file", http.MethodGet, append([]mcp.ToolOption{
+		mcp.WithDescription("Get the content of a File. Text files are returned as text, binary files as an embedded base64 resource."),
+		mcp.WithString("vaultUuid", mcp.Required(), mcp.Description("The UUID or name of the Vault the item is in")),
+		mcp.WithString("itemUuid", mcp.Required(), mcp.Description("The UUID or name of the Item the File is in")),
+		mcp.WithString("fileUuid", mcp.Required(), mcp.Description("UUID of the file to get content from")),
+	}, opts...)...)
+}
@@ -0,0 +1,59 @@
+// Code generated by openapigen from openapi.yaml. DO NOT EDIT.
+
+package tools
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/1password-connect/mcp-server/tools/common"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// getDetailsOfFileByIdParams holds the arguments of the get_vaults_vaultUuid_items_itemUuid_files_fileUuid tool.
+type getDetailsOfFileByIdParams struct {
+	VaultUuid   string // The UUID or name of the Vault to fetch Item from
+	ItemUuid    string // The UUID or name of the Item to fetch File from
+	FileUuid    string // The UUID of the File to fetch
+	InlineFiles *bool  // Tells server to return the base64-encoded file contents in the response.
+}
+
+// parseGetDetailsOfFileByIdParams reads the arguments of the get_vaults_vaultUuid_items_itemUuid_files_fileUuid tool.
+func parseGetDetailsOfFileByIdParams(args map[string]any) (getDetailsOfFileByIdParams, error) {
+	var p getDetailsOfFileByIdParams
+	var err error
+	if p.VaultUuid, err = common.PathParam(args, "vaultUuid"); err != nil {
+		return p, err
+	}
+	if p.ItemUuid, err = common.PathParam(args, "itemUuid"); err != nil {
+		return p, err
+	}
+	if p.FileUuid, err = common.PathParam(args, "fileUuid"); err != nil {
+		return p, err
+	}
+	if p.InlineFiles, err = common.QueryBool(args, "inline_files"); err != nil {
+		return p, err
+	}
+	return p, nil
+}
+
+// query returns the query parameters that are set.
+func (p getDetailsOfFileByIdParams) query() url.Values {
+	query := url.Values{}
+	if p.InlineFiles != nil {
+		query.Set("inline_files", strconv.FormatBool(*p.InlineFiles))
+	}
+	return query
+}
+
+// getDetailsOfFileByIdTool returns the definition of the get_vaults_vaultUuid_items_itemUuid_files_fileUuid tool, followed by opts.
+func getDetailsOfFileByIdTool(opts ...mcp.ToolOption) mcp.Tool {
+	return common.NewTool("get_vaults_vaultUuid_items_itemUuid_files_fileUuid", "Get file details", http.MethodGet, append([]mcp.ToolOption{
+		mcp.WithDescription("Get the details of a File"),
+		mcp.WithString("vaultUuid", mcp.Required(), mcp.Description("The UUID or name of the Vault to fetch Item from")),
+		mcp.WithString("itemUuid", mcp.Required(), mcp.Description("The UUID or name of the Item to fetch File from")),
+		mcp.WithString("fileUuid", mcp.Required(), mcp.Description("The UUID of the File to fetch")),
+		mcp.WithBoolean("inline_files", mcp.Description("Tells server to return the base64-encoded file contents in the response.")),
+	}, opts...)...)
+}
@@ -2,98 +2,55 @@ package tools
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"strings"
+	"time"
 
 	"github.com/1password-connect/mcp-server/config"
+	"github.com/1password-connect/mcp-server/connect"
 	"github.com/1password-connect/mcp-server/models"
+	"github.com/1password-connect/mcp-server/redact"
+	"github.com/1password-connect/mcp-server/tools/common"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
 func GetitemfilesHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	defaultClient := connect.NewClient(cfg)
+	policy := redact.New(cfg)
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		client := connect.ClientFor(ctx, defaultClient)
 		args, ok := request.Params.Arguments.(map[string]any)
 		if !ok {
 			return mcp.NewToolResultError("Invalid arguments object"), nil
 		}
-		vaultUuidVal, ok := args["vaultUuid"]
-		if !ok {
-			return mcp.NewToolResultError("Missing required path parameter: vaultUuid"), nil
-		}
-		vaultUuid, ok := vaultUuidVal.(string)
-		if !ok {
-			return mcp.NewToolResultError("Invalid path parameter: vaultUuid"), nil
-		}
-		itemUuidVal, ok := args["itemUuid"]
-		if !ok {
-			return mcp.NewToolResultError("Missing required path parameter: itemUuid"), nil
-		}
-		itemUuid, ok := itemUuidVal.(string)
-		if !ok {
-			return mcp.NewToolResultError("Invalid path parameter: itemUuid"), nil
-		}
-		queryParams := make([]string, 0)
-		if val, ok := args["inline_files"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("inline_files=%v", val))
-		}
-		queryString := ""
-		if len(queryParams) > 0 {
-			queryString = "?" + strings.Join(queryParams, "&")
-		}
-		url := fmt.Sprintf("%s/vaults/%s/items/%s/files%s", cfg.BaseURL, vaultUuid, itemUuid, queryString)
-		req, err := http.NewRequest("GET", url, nil)
+		params, err := parseGetItemFilesParams(args)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
+			return mcp.NewToolResultError(err.Error()), nil
 		}
-		// Set authentication based on auth type
-		if cfg.BearerToken != "" {
-			req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", cfg.BearerToken))
-		}
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
+		vaultUuid, err := client.ResolveVault(ctx, params.VaultUuid)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
+			return common.ErrorResult(err), nil
 		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
+		itemUuid, err := client.ResolveItem(ctx, vaultUuid, params.ItemUuid)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
-
-		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
+			return common.ErrorResult(err), nil
 		}
-		// Use properly typed response
-		var result []File
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
+		var result []models.File
+		if _, err := client.Get(ctx, connect.Path("vaults", vaultUuid, "items", itemUuid, "files"), params.query(), &result); err != nil {
+			return common.ErrorResult(err), nil
 		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		policy.Files(result, common.Reveal(args))
+		return common.ListResult("files", result), nil
 	}
 }
 
 func CreateGetitemfilesTool(cfg *config.APIConfig) models.Tool {
-	tool := mcp.NewTool("get_vaults_vaultUuid_items_itemUuid_files",
-		mcp.WithDescription("Get all the files inside an Item"),
-		mcp.WithString("vaultUuid", mcp.Required(), mcp.Description("The UUID of the Vault to fetch Items from")),
-		mcp.WithString("itemUuid", mcp.Required(), mcp.Description("The UUID of the Item to fetch files from")),
-		mcp.WithBoolean("inline_files", mcp.Description("Tells server to return the base64-encoded file contents in the response.")),
+	tool := getItemFilesTool(
+		common.WithListOutputSchema[models.File]("files"),
+		common.WithReveal(),
 	)
 
 	return models.Tool{
 		Definition: tool,
 		Handler:    GetitemfilesHandler(cfg),
+		Timeout:    2 * time.Minute,
 	}
 }
@@ -0,0 +1,54 @@
+// Code generated by openapigen from openapi.yaml. DO NOT EDIT.
+
+package tools
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/1password-connect/mcp-server/tools/common"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// getItemFilesParams holds the arguments of the get_vaults_vaultUuid_items_itemUuid_files tool.
+type getItemFilesParams struct {
+	VaultUuid   string // The UUID or name of the Vault to fetch Items from
+	ItemUuid    string // The UUID or name of the Item to fetch files from
+	InlineFiles *bool  // Tells server to return the base64-encoded file contents in the response.
+}
+
+// parseGetItemFilesParams reads the arguments of the get_vaults_vaultUuid_items_itemUuid_files tool.
+func parseGetItemFilesParams(args map[string]any) (getItemFilesParams, error) {
+	var p getItemFilesParams
+	var err error
+	if p.VaultUuid, err = common.PathParam(args, "vaultUuid"); err != nil {
+		return p, err
+	}
+	if p.ItemUuid, err = common.PathParam(args, "itemUuid"); err != nil {
+		return p, err
+	}
+	if p.InlineFiles, err = common.QueryBool(args, "inline_files"); err != nil {
+		return p, err
+	}
+	return p, nil
+}
+
+// query returns the query parameters that are set.
+func (p getItemFilesParams) query() url.Values {
+	query := url.Values{}
+	if p.InlineFiles != nil {
+		query.Set("inline_files", strconv.FormatBool(*p.InlineFiles))
+	}
+	return query
+}
+
+// getItemFilesTool returns the definition of the get_vaults_vaultUuid_items_itemUuid_files tool, followed by opts.
+func getItemFilesTool(opts ...mcp.ToolOption) mcp.Tool {
+	return common.NewTool("get_vaults_vaultUuid_items_itemUuid_files", "List item files", http.MethodGet, append([]mcp.ToolOption{
+		mcp.WithDescription("Get all the files inside an Item"),
+		mcp.WithString("vaultUuid", mcp.Required(), mcp.Description("The UUID or name of the Vault to fetch Items from")),
+		mcp.WithString("itemUuid", mcp.Required(), mcp.Description("The UUID or name of the Item to fetch files from")),
+		mcp.WithBoolean("inline_files", mcp.Description("Tells server to return the base64-encoded file contents in the response.")),
+	}, opts...)...)
+}
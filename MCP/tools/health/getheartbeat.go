@@ -2,63 +2,28 @@ package tools
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
 
 	"github.com/1password-connect/mcp-server/config"
+	"github.com/1password-connect/mcp-server/connect"
 	"github.com/1password-connect/mcp-server/models"
+	"github.com/1password-connect/mcp-server/tools/common"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
 func GetheartbeatHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	defaultClient := connect.NewClient(cfg)
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		url := fmt.Sprintf("%s/heartbeat", cfg.BaseURL)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// Set authentication based on auth type
-		if cfg.BearerToken != "" {
-			req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", cfg.BearerToken))
-		}
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
-		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
-
-		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
-		}
-		// Use properly typed response
+		client := connect.ClientFor(ctx, defaultClient)
 		var result string
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
+		if _, err := client.Get(ctx, connect.Path("heartbeat"), nil, &result); err != nil {
+			return common.ErrorResult(err), nil
 		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		return mcp.NewToolResultText(result), nil
 	}
 }
 
 func CreateGetheartbeatTool(cfg *config.APIConfig) models.Tool {
-	tool := mcp.NewTool("get_heartbeat",
-		mcp.WithDescription("Ping the server for liveness"),
-	)
+	tool := getHeartbeatTool()
 
 	return models.Tool{
 		Definition: tool,
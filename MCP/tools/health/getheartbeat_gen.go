@@ -0,0 +1,17 @@
+// Code generated by openapigen from openapi.yaml. DO NOT EDIT.
+
+package tools
+
+import (
+	"net/http"
+
+	"github.com/1password-connect/mcp-server/tools/common"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// getHeartbeatTool returns the definition of the get_heartbeat tool, followed by opts.
+func getHeartbeatTool(opts ...mcp.ToolOption) mcp.Tool {
+	return common.NewTool("get_heartbeat", "Check heartbeat", http.MethodGet, append([]mcp.ToolOption{
+		mcp.WithDescription("Ping the server for liveness"),
+	}, opts...)...)
+}
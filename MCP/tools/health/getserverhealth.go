@@ -2,62 +2,37 @@ package tools
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
 
 	"github.com/1password-connect/mcp-server/config"
+	"github.com/1password-connect/mcp-server/connect"
 	"github.com/1password-connect/mcp-server/models"
+	"github.com/1password-connect/mcp-server/tools/common"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
+// serverHealth is the response of GET /health, whose schema the OpenAPI
+// specification declares inline.
+type serverHealth struct {
+	Name         string                     `json:"name"`
+	Version      string                     `json:"version"` // The Connect server's version
+	Dependencies []models.ServiceDependency `json:"dependencies,omitempty"`
+}
+
 func GetserverhealthHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	defaultClient := connect.NewClient(cfg)
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		url := fmt.Sprintf("%s/health", cfg.BaseURL)
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// Set authentication based on auth type
-		if cfg.BearerToken != "" {
-			req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", cfg.BearerToken))
-		}
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
-		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
-
-		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
-		}
-		// Use properly typed response
+		client := connect.ClientFor(ctx, defaultClient)
 		var result map[string]interface{}
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
+		if _, err := client.Get(ctx, connect.Path("health"), nil, &result); err != nil {
+			return common.ErrorResult(err), nil
 		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		return common.JSONResult(result), nil
 	}
 }
 
 func CreateGetserverhealthTool(cfg *config.APIConfig) models.Tool {
-	tool := mcp.NewTool("get_health",
-		mcp.WithDescription("Get state of the server and its dependencies."),
+	tool := getServerHealthTool(
+		common.WithOutputSchema[serverHealth](),
 	)
 
 	return models.Tool{
@@ -0,0 +1,17 @@
+// Code generated by openapigen from openapi.yaml. DO NOT EDIT.
+
+package tools
+
+import (
+	"net/http"
+
+	"github.com/1password-connect/mcp-server/tools/common"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// getServerHealthTool returns the definition of the get_health tool, followed by opts.
+func getServerHealthTool(opts ...mcp.ToolOption) mcp.Tool {
+	return common.NewTool("get_health", "Get server health", http.MethodGet, append([]mcp.ToolOption{
+		mcp.WithDescription("Get state of the server and its dependencies."),
+	}, opts...)...)
+}
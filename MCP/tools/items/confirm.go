@@ -0,0 +1,208 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/1password-connect/mcp-server/config"
+	"github.com/1password-connect/mcp-server/connect"
+	"github.com/1password-connect/mcp-server/models"
+	"github.com/1password-connect/mcp-server/tools/common"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// confirmTimeout gives destructive tools time for the user to answer when
+// they ask for confirmation.
+func confirmTimeout(cfg *config.APIConfig) time.Duration {
+	if cfg.ConfirmDestructive {
+		return 5 * time.Minute
+	}
+	return 0
+}
+
+// confirmTarget fetches the vault and current item a destructive call would
+// change, so that the confirmation can name them.
+func confirmTarget(ctx context.Context, client *connect.Client, vaultID, itemID string) (*models.Vault, *models.FullItem, error) {
+	var vault models.Vault
+	if _, err := client.Get(ctx, connect.Path("vaults", vaultID), nil, &vault); err != nil {
+		return nil, nil, err
+	}
+	var item models.FullItem
+	if _, err := client.Get(ctx, connect.Path("vaults", vaultID, "items", itemID), nil, &item); err != nil {
+		return nil, nil, err
+	}
+	return &vault, &item, nil
+}
+
+// confirmOperation fetches the target of a destructive call and asks the
+// user to confirm the operation describe summarises. It returns nil when the
+// call may proceed.
+func confirmOperation(ctx context.Context, client *connect.Client, args map[string]any, vaultID, itemID string,
+	describe func(vault *models.Vault, item *models.FullItem) string) *mcp.CallToolResult {
+	vault, item, err := confirmTarget(ctx, client, vaultID, itemID)
+	if err != nil {
+		return common.ErrorResult(err)
+	}
+	return common.Confirm(ctx, args, describe(vault, item))
+}
+
+func describeDelete(vault *models.Vault, item *models.FullItem) string {
+	return fmt.Sprintf("Delete item %q from vault %q? This cannot be undone.", item.Title, vault.Name)
+}
+
+// describeReplace lists what replacing item with replacement changes. Values
+// are never shown, only which fields they belong to.
+func describeReplace(vault *models.Vault, item, replacement *models.FullItem) string {
+	var changes []string
+	if replacement.Title != item.Title {
+		changes = append(changes, fmt.Sprintf("rename it to %q", replacement.Title))
+	}
+	current := make(map[string]models.Field, len(item.Fields))
+	for _, f := range item.Fields {
+		current[f.Id] = f
+	}
+	var added, changed []string
+	for _, f := range replacement.Fields {
+		old, ok := current[f.Id]
+		switch {
+		case !ok:
+			added = append(added, fieldName(f))
+		case old.Value != f.Value || old.Label != f.Label || old.TypeField != f.TypeField:
+			changed = append(changed, fieldName(f))
+		}
+		delete(current, f.Id)
+	}
+	var removed []string
+	for _, f := range item.Fields {
+		if _, ok := current[f.Id]; ok {
+			removed = append(removed, fieldName(f))
+		}
+	}
+	if len(removed) > 0 {
+		changes = append(changes, "remove fields "+strings.Join(removed, ", "))
+	}
+	if len(changed) > 0 {
+		changes = append(changes, "change fields "+strings.Join(changed, ", "))
+	}
+	if len(added) > 0 {
+		changes = append(changes, "add fields "+strings.Join(added, ", "))
+	}
+	if len(replacement.Files) < len(item.Files) {
+		changes = append(changes, fmt.Sprintf("drop %d of %d files", len(item.Files)-len(replacement.Files), len(item.Files)))
+	}
+	if len(changes) == 0 {
+		changes = append(changes, "leave its fields as they are")
+	}
+	return fmt.Sprintf("Replace item %q in vault %q? This will %s.", item.Title, vault.Name, strings.Join(changes, "; "))
+}
+
+// patchMayRemove reports whether patch has operations that could remove
+// something from the item, so that patchRemovals needs the current item.
+func patchMayRemove(patch models.Patch) bool {
+	return slices.ContainsFunc(patch, func(op models.PatchOperation) bool {
+		return op.Op == models.PatchRemove || op.Op == models.PatchReplace && op.Path != "/title"
+	})
+}
+
+// patchRemovals returns the paths of the operations in patch that remove
+// something from item: remove operations, replacements of the tags or
+// sections that leave some out, and replacements that clear a field's value.
+// It returns nil when the patch removes nothing.
+func patchRemovals(patch models.Patch, item *models.FullItem) []string {
+	var paths []string
+	for _, op := range patch {
+		if op.Op == models.PatchRemove || op.Op == models.PatchReplace && replaceRemoves(op, item) {
+			paths = append(paths, op.Path)
+		}
+	}
+	return paths
+}
+
+// replaceRemoves reports whether a replace operation drops tags or sections
+// of item, or clears the value of one of its fields. A value that cannot be
+// decoded counts as a removal.
+func replaceRemoves(op models.PatchOperation, item *models.FullItem) bool {
+	switch op.Path {
+	case "/tags":
+		var tags []string
+		if err := decodePatchValue(op.Value, &tags); err != nil {
+			var tag string
+			if err := decodePatchValue(op.Value, &tag); err != nil {
+				return true
+			}
+			tags = []string{tag}
+		}
+		return slices.ContainsFunc(item.Tags, func(tag string) bool { return !slices.Contains(tags, tag) })
+	case "/sections":
+		var sections []map[string]any
+		if err := decodePatchValue(op.Value, &sections); err != nil {
+			return true
+		}
+		return slices.ContainsFunc(item.Sections, func(current map[string]any) bool {
+			return !slices.ContainsFunc(sections, func(s map[string]any) bool { return s["id"] == current["id"] })
+		})
+	}
+
+	rest, ok := strings.CutPrefix(op.Path, "/fields/")
+	if !ok {
+		return false
+	}
+	id, attr, _ := strings.Cut(rest, "/")
+	i := slices.IndexFunc(item.Fields, func(f models.Field) bool { return f.Id == id })
+	if i < 0 || item.Fields[i].Value == "" {
+		return false
+	}
+	switch attr {
+	case "":
+		var field models.Field
+		if err := decodePatchValue(op.Value, &field); err != nil {
+			return true
+		}
+		return field.Value == "" && !field.Generate
+	case "value":
+		var value string
+		if err := decodePatchValue(op.Value, &value); err != nil {
+			return true
+		}
+		return value == ""
+	}
+	return false
+}
+
+// decodePatchValue decodes the value of a patch operation, as sent by the
+// client or built by a tool, into v.
+func decodePatchValue(value, v any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// describePatchRemovals names the fields behind the paths of the operations
+// that remove something from item.
+func describePatchRemovals(vault *models.Vault, item *models.FullItem, paths []string) string {
+	names := make([]string, len(paths))
+	for i, path := range paths {
+		names[i] = path
+		if id, ok := strings.CutPrefix(path, "/fields/"); ok {
+			for _, f := range item.Fields {
+				if f.Id == strings.SplitN(id, "/", 2)[0] {
+					names[i] = fmt.Sprintf("%s (%s)", path, fieldName(f))
+				}
+			}
+		}
+	}
+	return fmt.Sprintf("Patch item %q in vault %q, removing %s?", item.Title, vault.Name, strings.Join(names, ", "))
+}
+
+func fieldName(f models.Field) string {
+	if f.Label != "" {
+		return fmt.Sprintf("%q", f.Label)
+	}
+	return f.Id
+}
@@ -0,0 +1,39 @@
+// Code generated by openapigen from openapi.yaml. DO NOT EDIT.
+
+package tools
+
+import (
+	"net/http"
+
+	"github.com/1password-connect/mcp-server/models"
+	"github.com/1password-connect/mcp-server/tools/common"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// createVaultItemParams holds the arguments of the post_vaults_vaultUuid_items tool.
+type createVaultItemParams struct {
+	VaultUuid string // The UUID or name of the Vault to create an Item in
+	Body      models.FullItem
+}
+
+// parseCreateVaultItemParams reads the arguments of the post_vaults_vaultUuid_items tool.
+func parseCreateVaultItemParams(args map[string]any) (createVaultItemParams, error) {
+	var p createVaultItemParams
+	var err error
+	if p.VaultUuid, err = common.PathParam(args, "vaultUuid"); err != nil {
+		return p, err
+	}
+	if err := common.DecodeBody(args, &p.Body); err != nil {
+		return p, err
+	}
+	return p, nil
+}
+
+// createVaultItemTool returns the definition of the post_vaults_vaultUuid_items tool, followed by opts.
+func createVaultItemTool(opts ...mcp.ToolOption) mcp.Tool {
+	return common.NewTool("post_vaults_vaultUuid_items", "Create item", http.MethodPost, append([]mcp.ToolOption{
+		mcp.WithDescription("Create a new Item. `vault` may be left out; it defaults to the Vault given by vaultUuid."),
+		mcp.WithString("vaultUuid", mcp.Required(), mcp.Description("The UUID or name of the Vault to create an Item in")),
+		common.WithBody(fullItemRequestSchema, "vault"),
+	}, opts...)...)
+}
@@ -0,0 +1,38 @@
+// Code generated by openapigen from openapi.yaml. DO NOT EDIT.
+
+package tools
+
+import (
+	"net/http"
+
+	"github.com/1password-connect/mcp-server/tools/common"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// deleteVaultItemParams holds the arguments of the delete_vaults_vaultUuid_items_itemUuid tool.
+type deleteVaultItemParams struct {
+	VaultUuid string // The UUID or name of the Vault the item is in
+	ItemUuid  string // The UUID or name of the Item to update
+}
+
+// parseDeleteVaultItemParams reads the arguments of the delete_vaults_vaultUuid_items_itemUuid tool.
+func parseDeleteVaultItemParams(args map[string]any) (deleteVaultItemParams, error) {
+	var p deleteVaultItemParams
+	var err error
+	if p.VaultUuid, err = common.PathParam(args, "vaultUuid"); err != nil {
+		return p, err
+	}
+	if p.ItemUuid, err = common.PathParam(args, "itemUuid"); err != nil {
+		return p, err
+	}
+	return p, nil
+}
+
+// deleteVaultItemTool returns the definition of the delete_vaults_vaultUuid_items_itemUuid tool, followed by opts.
+func deleteVaultItemTool(opts ...mcp.ToolOption) mcp.Tool {
+	return common.NewTool("delete_vaults_vaultUuid_items_itemUuid", "Delete item", http.MethodDelete, append([]mcp.ToolOption{
+		mcp.WithDescription("Delete an Item"),
+		mcp.WithString("vaultUuid", mcp.Required(), mcp.Description("The UUID or name of the Vault the item is in")),
+		mcp.WithString("itemUuid", mcp.Required(), mcp.Description("The UUID or name of the Item to update")),
+	}, opts...)...)
+}
@@ -0,0 +1,157 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/1password-connect/mcp-server/connect"
+	"github.com/1password-connect/mcp-server/models"
+	"github.com/1password-connect/mcp-server/secretref"
+	"github.com/1password-connect/mcp-server/tools/common"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// fieldSelector addresses a single field of an item for the field tools.
+type fieldSelector struct {
+	Field   string // Label or ID
+	Purpose string
+	Section string // Label or ID; empty for the whole item
+}
+
+// parseFieldSelector reads the field, purpose and section arguments, of
+// which field or purpose must be given.
+func parseFieldSelector(args map[string]any) (fieldSelector, error) {
+	var s fieldSelector
+	for _, arg := range []struct {
+		name string
+		dest *string
+	}{{"field", &s.Field}, {"purpose", &s.Purpose}, {"section", &s.Section}} {
+		val, ok := args[arg.name]
+		if !ok || val == nil {
+			continue
+		}
+		if *arg.dest, ok = val.(string); !ok {
+			return s, fmt.Errorf("Invalid parameter: %s", arg.name)
+		}
+	}
+	if s.Field == "" && s.Purpose == "" {
+		return s, fmt.Errorf("Missing required parameter: field or purpose")
+	}
+	return s, nil
+}
+
+// find returns the field s addresses within item. A field given by purpose
+// as well as by label or ID must match both.
+func (s fieldSelector) find(item *models.FullItem) (*models.Field, error) {
+	fields, err := secretref.SectionFields(item, s.Section)
+	if err != nil {
+		return nil, err
+	}
+	if s.Purpose == "" {
+		return secretref.MatchField(fields, s.Field)
+	}
+	var matching []models.Field
+	for _, f := range fields {
+		if strings.EqualFold(f.Purpose, s.Purpose) {
+			matching = append(matching, f)
+		}
+	}
+	if s.Field != "" {
+		return secretref.MatchField(matching, s.Field)
+	}
+	switch len(matching) {
+	case 0:
+		return nil, &connect.ResolveError{Kind: connect.KindNotFound, Resource: "field", Name: s.Purpose, Attribute: "purpose"}
+	case 1:
+		return &matching[0], nil
+	}
+	candidates := make([]connect.Candidate, len(matching))
+	for i, f := range matching {
+		candidates[i] = connect.Candidate{ID: f.Id, Name: f.Label}
+	}
+	return nil, &connect.ResolveError{Kind: connect.KindAmbiguous, Resource: "field", Name: s.Purpose, Attribute: "purpose", Candidates: candidates}
+}
+
+// fieldTarget is the field a call of a field tool addresses.
+type fieldTarget struct {
+	VaultID string
+	ItemID  string
+	Field   models.Field
+}
+
+// fetchField resolves the vault and item of a field tool's call, fetches the
+// item and finds the field. It returns a tool result instead when the call
+// fails.
+func fetchField(ctx context.Context, client *connect.Client, args map[string]any) (fieldTarget, *mcp.CallToolResult) {
+	var target fieldTarget
+	vault, err := common.PathParam(args, "vaultUuid")
+	if err != nil {
+		return target, mcp.NewToolResultError(err.Error())
+	}
+	item, err := common.PathParam(args, "itemUuid")
+	if err != nil {
+		return target, mcp.NewToolResultError(err.Error())
+	}
+	selector, err := parseFieldSelector(args)
+	if err != nil {
+		return target, mcp.NewToolResultError(err.Error())
+	}
+	if target.VaultID, err = client.ResolveVault(ctx, vault); err != nil {
+		return target, common.ErrorResult(err)
+	}
+	if target.ItemID, err = client.ResolveItem(ctx, target.VaultID, item); err != nil {
+		return target, common.ErrorResult(err)
+	}
+	var full models.FullItem
+	if _, err := client.Get(ctx, connect.Path("vaults", target.VaultID, "items", target.ItemID), nil, &full); err != nil {
+		return target, common.ErrorResult(err)
+	}
+	field, err := selector.find(&full)
+	if err != nil {
+		return target, common.ErrorResult(err)
+	}
+	target.Field = *field
+	return target, nil
+}
+
+// withFieldSelector adds the arguments addressing an item's field.
+func withFieldSelector() mcp.ToolOption {
+	return func(t *mcp.Tool) {
+		mcp.WithString("vaultUuid", mcp.Required(), mcp.Description("The UUID or name of the Vault the item is in"))(t)
+		mcp.WithString("itemUuid", mcp.Required(), mcp.Description("The UUID or name of the Item"))(t)
+		mcp.WithString("field", mcp.Description("The label or ID of the field"))(t)
+		withFieldProperty("purpose", "The purpose of the field, such as PASSWORD for the password of a Login")(t)
+		mcp.WithString("section", mcp.Description("The label or ID of the section the field is in"))(t)
+	}
+}
+
+// withFieldProperty adds the schema of the Field property name, taken from
+// the generated FullItem request schema so that its enums and limits match
+// the document, as an optional argument.
+func withFieldProperty(name, description string) mcp.ToolOption {
+	var schema struct {
+		Properties struct {
+			Fields struct {
+				Items struct {
+					Properties map[string]map[string]any `json:"properties"`
+				} `json:"items"`
+			} `json:"fields"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal([]byte(fullItemRequestSchema), &schema); err != nil {
+		panic(fmt.Sprintf("invalid request body schema: %v", err))
+	}
+	property, ok := schema.Properties.Fields.Items.Properties[name]
+	if !ok {
+		panic(fmt.Sprintf("the Field schema has no property %s", name))
+	}
+	property["description"] = description
+	return func(t *mcp.Tool) {
+		if t.InputSchema.Properties == nil {
+			t.InputSchema.Properties = make(map[string]any)
+		}
+		t.InputSchema.Properties[name] = property
+	}
+}
@@ -0,0 +1,45 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/1password-connect/mcp-server/config"
+	"github.com/1password-connect/mcp-server/connect"
+	"github.com/1password-connect/mcp-server/models"
+	"github.com/1password-connect/mcp-server/redact"
+	"github.com/1password-connect/mcp-server/tools/common"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func GetitemfieldHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	defaultClient := connect.NewClient(cfg)
+	policy := redact.New(cfg)
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		client := connect.ClientFor(ctx, defaultClient)
+		args, ok := request.Params.Arguments.(map[string]any)
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments object"), nil
+		}
+		target, result := fetchField(ctx, client, args)
+		if result != nil {
+			return result, nil
+		}
+		policy.Field(&target.Field, common.Reveal(args))
+		return common.JSONResult(target.Field), nil
+	}
+}
+
+func CreateGetitemfieldTool(cfg *config.APIConfig) models.Tool {
+	tool := common.NewTool("get_item_field", "Get item field", http.MethodGet,
+		mcp.WithDescription("Get a single field of an Item instead of the whole Item. Address the field by its label or ID, by its purpose, or by both, optionally within a section. A TOTP field includes its current one-time password"),
+		common.WithOutputSchema[models.Field](),
+		withFieldSelector(),
+		common.WithReveal(),
+	)
+
+	return models.Tool{
+		Definition: tool,
+		Handler:    GetitemfieldHandler(cfg),
+	}
+}
@@ -0,0 +1,38 @@
+// Code generated by openapigen from openapi.yaml. DO NOT EDIT.
+
+package tools
+
+import (
+	"net/http"
+
+	"github.com/1password-connect/mcp-server/tools/common"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// getVaultItemByIdParams holds the arguments of the get_vaults_vaultUuid_items_itemUuid tool.
+type getVaultItemByIdParams struct {
+	VaultUuid string // The UUID or name of the Vault to fetch Item from
+	ItemUuid  string // The UUID or name of the Item to fetch
+}
+
+// parseGetVaultItemByIdParams reads the arguments of the get_vaults_vaultUuid_items_itemUuid tool.
+func parseGetVaultItemByIdParams(args map[string]any) (getVaultItemByIdParams, error) {
+	var p getVaultItemByIdParams
+	var err error
+	if p.VaultUuid, err = common.PathParam(args, "vaultUuid"); err != nil {
+		return p, err
+	}
+	if p.ItemUuid, err = common.PathParam(args, "itemUuid"); err != nil {
+		return p, err
+	}
+	return p, nil
+}
+
+// getVaultItemByIdTool returns the definition of the get_vaults_vaultUuid_items_itemUuid tool, followed by opts.
+func getVaultItemByIdTool(opts ...mcp.ToolOption) mcp.Tool {
+	return common.NewTool("get_vaults_vaultUuid_items_itemUuid", "Get item", http.MethodGet, append([]mcp.ToolOption{
+		mcp.WithDescription("Get the details of an Item"),
+		mcp.WithString("vaultUuid", mcp.Required(), mcp.Description("The UUID or name of the Vault to fetch Item from")),
+		mcp.WithString("itemUuid", mcp.Required(), mcp.Description("The UUID or name of the Item to fetch")),
+	}, opts...)...)
+}
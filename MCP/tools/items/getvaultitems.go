@@ -2,89 +2,47 @@ package tools
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"strings"
+	"time"
 
 	"github.com/1password-connect/mcp-server/config"
+	"github.com/1password-connect/mcp-server/connect"
 	"github.com/1password-connect/mcp-server/models"
+	"github.com/1password-connect/mcp-server/tools/common"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
 func GetvaultitemsHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	defaultClient := connect.NewClient(cfg)
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		client := connect.ClientFor(ctx, defaultClient)
 		args, ok := request.Params.Arguments.(map[string]any)
 		if !ok {
 			return mcp.NewToolResultError("Invalid arguments object"), nil
 		}
-		vaultUuidVal, ok := args["vaultUuid"]
-		if !ok {
-			return mcp.NewToolResultError("Missing required path parameter: vaultUuid"), nil
-		}
-		vaultUuid, ok := vaultUuidVal.(string)
-		if !ok {
-			return mcp.NewToolResultError("Invalid path parameter: vaultUuid"), nil
-		}
-		queryParams := make([]string, 0)
-		if val, ok := args["filter"]; ok {
-			queryParams = append(queryParams, fmt.Sprintf("filter=%v", val))
-		}
-		queryString := ""
-		if len(queryParams) > 0 {
-			queryString = "?" + strings.Join(queryParams, "&")
-		}
-		url := fmt.Sprintf("%s/vaults/%s/items%s", cfg.BaseURL, vaultUuid, queryString)
-		req, err := http.NewRequest("GET", url, nil)
+		params, err := parseGetVaultItemsParams(args)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
-		}
-		// Set authentication based on auth type
-		if cfg.BearerToken != "" {
-			req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", cfg.BearerToken))
+			return mcp.NewToolResultError(err.Error()), nil
 		}
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
+		vaultUuid, err := client.ResolveVault(ctx, params.VaultUuid)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
+			return common.ErrorResult(err), nil
 		}
-		defer resp.Body.Close()
-
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
-
-		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
-		}
-		// Use properly typed response
-		var result []Item
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
+		var result []models.Item
+		if _, err := client.Get(ctx, connect.Path("vaults", vaultUuid, "items"), params.query(), &result); err != nil {
+			return common.ErrorResult(err), nil
 		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		return common.ListResult("items", result), nil
 	}
 }
 
 func CreateGetvaultitemsTool(cfg *config.APIConfig) models.Tool {
-	tool := mcp.NewTool("get_vaults_vaultUuid_items",
-		mcp.WithDescription("Get all items for inside a Vault"),
-		mcp.WithString("vaultUuid", mcp.Required(), mcp.Description("The UUID of the Vault to fetch Items from")),
-		mcp.WithString("filter", mcp.Description("Filter the Item collection based on Item name using SCIM eq filter")),
+	tool := getVaultItemsTool(
+		common.WithListOutputSchema[models.Item]("items"),
 	)
 
 	return models.Tool{
 		Definition: tool,
 		Handler:    GetvaultitemsHandler(cfg),
+		Timeout:    2 * time.Minute,
 	}
 }
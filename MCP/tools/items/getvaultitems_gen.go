@@ -0,0 +1,48 @@
+// Code generated by openapigen from openapi.yaml. DO NOT EDIT.
+
+package tools
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/1password-connect/mcp-server/tools/common"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// getVaultItemsParams holds the arguments of the get_vaults_vaultUuid_items tool.
+type getVaultItemsParams struct {
+	VaultUuid string  // The UUID or name of the Vault to fetch Items from
+	Filter    *string // Filter the Item collection based on Item name using SCIM eq filter
+}
+
+// parseGetVaultItemsParams reads the arguments of the get_vaults_vaultUuid_items tool.
+func parseGetVaultItemsParams(args map[string]any) (getVaultItemsParams, error) {
+	var p getVaultItemsParams
+	var err error
+	if p.VaultUuid, err = common.PathParam(args, "vaultUuid"); err != nil {
+		return p, err
+	}
+	if p.Filter, err = common.QueryString(args, "filter"); err != nil {
+		return p, err
+	}
+	return p, nil
+}
+
+// query returns the query parameters that are set.
+func (p getVaultItemsParams) query() url.Values {
+	query := url.Values{}
+	if p.Filter != nil {
+		query.Set("filter", *p.Filter)
+	}
+	return query
+}
+
+// getVaultItemsTool returns the definition of the get_vaults_vaultUuid_items tool, followed by opts.
+func getVaultItemsTool(opts ...mcp.ToolOption) mcp.Tool {
+	return common.NewTool("get_vaults_vaultUuid_items", "List items", http.MethodGet, append([]mcp.ToolOption{
+		mcp.WithDescription("Get all items for inside a Vault"),
+		mcp.WithString("vaultUuid", mcp.Required(), mcp.Description("The UUID or name of the Vault to fetch Items from")),
+		mcp.WithString("filter", mcp.Description("Filter the Item collection based on Item name using SCIM eq filter")),
+	}, opts...)...)
+}
@@ -2,107 +2,100 @@ package tools
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"bytes"
 
 	"github.com/1password-connect/mcp-server/config"
+	"github.com/1password-connect/mcp-server/connect"
 	"github.com/1password-connect/mcp-server/models"
+	"github.com/1password-connect/mcp-server/redact"
+	"github.com/1password-connect/mcp-server/tools/common"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
 func PatchvaultitemHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	defaultClient := connect.NewClient(cfg)
+	policy := redact.New(cfg)
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		client := connect.ClientFor(ctx, defaultClient)
 		args, ok := request.Params.Arguments.(map[string]any)
 		if !ok {
 			return mcp.NewToolResultError("Invalid arguments object"), nil
 		}
-		vaultUuidVal, ok := args["vaultUuid"]
-		if !ok {
-			return mcp.NewToolResultError("Missing required path parameter: vaultUuid"), nil
-		}
-		vaultUuid, ok := vaultUuidVal.(string)
-		if !ok {
-			return mcp.NewToolResultError("Invalid path parameter: vaultUuid"), nil
-		}
-		itemUuidVal, ok := args["itemUuid"]
-		if !ok {
-			return mcp.NewToolResultError("Missing required path parameter: itemUuid"), nil
-		}
-		itemUuid, ok := itemUuidVal.(string)
-		if !ok {
-			return mcp.NewToolResultError("Invalid path parameter: itemUuid"), nil
+		params, err := parsePatchVaultItemParams(args)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
 		}
-		// Create properly typed request body using the generated schema
-		var requestBody []map[string]interface{}
-		
-		// Optimized: Single marshal/unmarshal with JSON tags handling field mapping
-		if argsJSON, err := json.Marshal(args); err == nil {
-			if err := json.Unmarshal(argsJSON, &requestBody); err != nil {
-				return mcp.NewToolResultError(fmt.Sprintf("Failed to convert arguments to request type: %v", err)), nil
-			}
-		} else {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal arguments: %v", err)), nil
+		if err := params.Body.Validate(); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
 		}
-		
-		bodyBytes, err := json.Marshal(requestBody)
+		vaultUuid, err := client.ResolveVault(ctx, params.VaultUuid)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to encode request body", err), nil
+			return common.ErrorResult(err), nil
 		}
-		url := fmt.Sprintf("%s/vaults/%s/items/%s", cfg.BaseURL, vaultUuid, itemUuid)
-		req, err := http.NewRequest("PATCH", url, bytes.NewBuffer(bodyBytes))
-		req.Header.Set("Content-Type", "application/json")
+		itemUuid, err := client.ResolveItem(ctx, vaultUuid, params.ItemUuid)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
+			return common.ErrorResult(err), nil
 		}
-		// Set authentication based on auth type
-		if cfg.BearerToken != "" {
-			req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", cfg.BearerToken))
+		requestBody := params.Body
+		if cfg.ConfirmDestructive && patchMayRemove(requestBody) {
+			vault, item, err := confirmTarget(ctx, client, vaultUuid, itemUuid)
+			if err != nil {
+				return common.ErrorResult(err), nil
+			}
+			if removals := patchRemovals(requestBody, item); len(removals) > 0 {
+				if result := common.Confirm(ctx, args, describePatchRemovals(vault, item, removals)); result != nil {
+					return result, nil
+				}
+			}
 		}
-		req.Header.Set("Accept", "application/json")
 
-		resp, err := http.DefaultClient.Do(req)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
+		if idempotentPatch(requestBody) {
+			ctx = connect.WithIdempotentPatch(ctx)
 		}
-		defer resp.Body.Close()
 
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
-		}
-
-		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
-		}
-		// Use properly typed response
 		var result models.FullItem
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
+		if _, err := client.Patch(ctx, connect.Path("vaults", vaultUuid, "items", itemUuid), requestBody, &result); err != nil {
+			return common.ErrorResult(err), nil
 		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		policy.Item(&result, common.Reveal(args))
+		return common.JSONResult(result), nil
 	}
 }
 
 func CreatePatchvaultitemTool(cfg *config.APIConfig) models.Tool {
-	tool := mcp.NewTool("patch_vaults_vaultUuid_items_itemUuid",
-		mcp.WithDescription("Update a subset of Item attributes"),
-		mcp.WithString("vaultUuid", mcp.Required(), mcp.Description("The UUID of the Vault the item is in")),
-		mcp.WithString("itemUuid", mcp.Required(), mcp.Description("The UUID of the Item to update")),
-		mcp.WithArray("items", mcp.Required(), mcp.Description("Array of objects")),
+	tool := patchVaultItemTool(
+		common.WithOutputSchema[models.FullItem](),
+		common.WithReveal(),
+		common.WithConfirm(cfg),
 	)
 
 	return models.Tool{
 		Definition: tool,
 		Handler:    PatchvaultitemHandler(cfg),
+		Timeout:    confirmTimeout(cfg),
+	}
+}
+
+// idempotentPatch reports whether patch only replaces values, so that
+// applying it twice yields the same item and it is safe to retry. A replaced
+// field that asks for a generated value would get a new one on every try.
+func idempotentPatch(patch models.Patch) bool {
+	for _, op := range patch {
+		if op.Op != models.PatchReplace || generatesValue(op.Value) {
+			return false
+		}
+	}
+	return true
+}
+
+// generatesValue reports whether a patch value is a field that asks Connect
+// to generate its value.
+func generatesValue(value any) bool {
+	switch v := value.(type) {
+	case models.Field:
+		return v.Generate
+	case map[string]any:
+		generate, _ := v["generate"].(bool)
+		return generate
 	}
+	return false
 }
@@ -0,0 +1,44 @@
+// Code generated by openapigen from openapi.yaml. DO NOT EDIT.
+
+package tools
+
+import (
+	"net/http"
+
+	"github.com/1password-connect/mcp-server/models"
+	"github.com/1password-connect/mcp-server/tools/common"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// patchVaultItemParams holds the arguments of the patch_vaults_vaultUuid_items_itemUuid tool.
+type patchVaultItemParams struct {
+	VaultUuid string // The UUID or name of the Vault the item is in
+	ItemUuid  string // The UUID or name of the Item to update
+	Body      models.Patch
+}
+
+// parsePatchVaultItemParams reads the arguments of the patch_vaults_vaultUuid_items_itemUuid tool.
+func parsePatchVaultItemParams(args map[string]any) (patchVaultItemParams, error) {
+	var p patchVaultItemParams
+	var err error
+	if p.VaultUuid, err = common.PathParam(args, "vaultUuid"); err != nil {
+		return p, err
+	}
+	if p.ItemUuid, err = common.PathParam(args, "itemUuid"); err != nil {
+		return p, err
+	}
+	if err := common.DecodeBodyArg(args, "items", &p.Body); err != nil {
+		return p, err
+	}
+	return p, nil
+}
+
+// patchVaultItemTool returns the definition of the patch_vaults_vaultUuid_items_itemUuid tool, followed by opts.
+func patchVaultItemTool(opts ...mcp.ToolOption) mcp.Tool {
+	return common.NewTool("patch_vaults_vaultUuid_items_itemUuid", "Patch item", http.MethodPatch, append([]mcp.ToolOption{
+		mcp.WithDescription("Update a subset of Item attributes with an [RFC6902 JSON Patch](https://tools.ietf.org/html/rfc6902) document. Only `add`, `remove` and `replace` operations are supported, on these paths:\n\n- `/title`\n- `/tags`, where adding a single tag appends it\n- `/sections`\n- `/fields`, to add a field\n- `/fields/{fieldId}`, to replace or remove a field\n- `/fields/{fieldId}/value`"),
+		mcp.WithString("vaultUuid", mcp.Required(), mcp.Description("The UUID or name of the Vault the item is in")),
+		mcp.WithString("itemUuid", mcp.Required(), mcp.Description("The UUID or name of the Item to update")),
+		common.WithBodyArg("items", patchRequestSchema),
+	}, opts...)...)
+}
@@ -0,0 +1,286 @@
+// Code generated by openapigen from openapi.yaml. DO NOT EDIT.
+
+package tools
+
+// fullItemRequestSchema is the FullItem schema as accepted in request bodies.
+const fullItemRequestSchema = `{
+  "properties": {
+    "category": {
+      "enum": [
+        "LOGIN",
+        "PASSWORD",
+        "API_CREDENTIAL",
+        "SERVER",
+        "DATABASE",
+        "CREDIT_CARD",
+        "MEMBERSHIP",
+        "PASSPORT",
+        "SOFTWARE_LICENSE",
+        "OUTDOOR_LICENSE",
+        "SECURE_NOTE",
+        "WIRELESS_ROUTER",
+        "BANK_ACCOUNT",
+        "DRIVER_LICENSE",
+        "IDENTITY",
+        "REWARD_PROGRAM",
+        "DOCUMENT",
+        "EMAIL_ACCOUNT",
+        "SOCIAL_SECURITY_NUMBER",
+        "MEDICAL_RECORD",
+        "SSH_KEY",
+        "CUSTOM"
+      ],
+      "type": "string"
+    },
+    "favorite": {
+      "default": false,
+      "type": "boolean"
+    },
+    "fields": {
+      "items": {
+        "properties": {
+          "generate": {
+            "default": false,
+            "description": "If value is not present then a new value should be generated for this field",
+            "type": "boolean"
+          },
+          "id": {
+            "type": "string"
+          },
+          "label": {
+            "type": "string"
+          },
+          "purpose": {
+            "description": "Some item types, Login and Password, have fields used for autofill. This property indicates that purpose and is required for some item types.",
+            "enum": [
+              "",
+              "USERNAME",
+              "PASSWORD",
+              "NOTES"
+            ],
+            "type": "string"
+          },
+          "recipe": {
+            "description": "The recipe is used in conjunction with the \"generate\" property to set the character set used to generate a new secure value",
+            "properties": {
+              "characterSets": {
+                "items": {
+                  "enum": [
+                    "LETTERS",
+                    "DIGITS",
+                    "SYMBOLS"
+                  ],
+                  "type": "string"
+                },
+                "maximum": 3,
+                "minimum": 0,
+                "type": "array",
+                "uniqueItems": true
+              },
+              "excludeCharacters": {
+                "description": "List of all characters that should be excluded from generated passwords.",
+                "examples": [
+                  "abc1"
+                ],
+                "type": "string"
+              },
+              "length": {
+                "default": 32,
+                "description": "Length of the generated value",
+                "maximum": 64,
+                "minimum": 1,
+                "type": "integer"
+              }
+            },
+            "type": "object"
+          },
+          "section": {
+            "properties": {
+              "id": {
+                "type": "string"
+              }
+            },
+            "type": "object"
+          },
+          "type": {
+            "default": "STRING",
+            "enum": [
+              "STRING",
+              "EMAIL",
+              "CONCEALED",
+              "URL",
+              "TOTP",
+              "DATE",
+              "MONTH_YEAR",
+              "MENU"
+            ],
+            "type": "string"
+          },
+          "value": {
+            "type": "string"
+          }
+        },
+        "required": [
+          "id",
+          "type"
+        ],
+        "type": "object"
+      },
+      "type": "array"
+    },
+    "files": {
+      "items": {
+        "examples": [
+          {
+            "content": "VGhlIGZ1dHVyZSBiZWxvbmdzIHRvIHRoZSBjdXJpb3VzLgo=",
+            "content_path": "v1/vaults/ionaiwtdvgclrixbt6ztpqcxnq/items/p7eflcy7f5mk7vg6zrzf5rjjyu/files/6r65pjq33banznomn7q22sj44e/content",
+            "id": "6r65pjq33banznomn7q22sj44e",
+            "name": "foo.txt",
+            "size": 35
+          }
+        ],
+        "properties": {
+          "content": {
+            "description": "Base64-encoded contents of the file. Only set if size \u003c= OP_MAX_INLINE_FILE_SIZE_KB kb and ` + "`" + `inline_files` + "`" + ` is set to ` + "`" + `true` + "`" + `.",
+            "format": "byte",
+            "type": "string"
+          },
+          "id": {
+            "description": "ID of the file",
+            "type": "string"
+          },
+          "name": {
+            "description": "Name of the file",
+            "type": "string"
+          },
+          "section": {
+            "description": "For files that are in a section, this field describes the section.",
+            "properties": {
+              "id": {
+                "type": "string"
+              }
+            },
+            "type": "object"
+          },
+          "size": {
+            "description": "Size in bytes of the file",
+            "type": "integer"
+          }
+        },
+        "type": "object"
+      },
+      "type": "array"
+    },
+    "id": {
+      "pattern": "^[\\da-z]{26}$",
+      "type": "string"
+    },
+    "sections": {
+      "items": {
+        "properties": {
+          "id": {
+            "type": "string"
+          },
+          "label": {
+            "type": "string"
+          }
+        },
+        "type": "object"
+      },
+      "type": "array"
+    },
+    "tags": {
+      "items": {
+        "type": "string"
+      },
+      "type": "array"
+    },
+    "title": {
+      "type": "string"
+    },
+    "urls": {
+      "examples": [
+        [
+          {
+            "href": "https://example.com",
+            "primary": true
+          },
+          {
+            "href": "https://example.org"
+          }
+        ]
+      ],
+      "items": {
+        "properties": {
+          "href": {
+            "format": "url",
+            "type": "string"
+          },
+          "label": {
+            "type": "string"
+          },
+          "primary": {
+            "type": "boolean"
+          }
+        },
+        "required": [
+          "href"
+        ],
+        "type": "object"
+      },
+      "type": "array"
+    },
+    "vault": {
+      "properties": {
+        "id": {
+          "pattern": "^[\\da-z]{26}$",
+          "type": "string"
+        }
+      },
+      "required": [
+        "id"
+      ],
+      "type": "object"
+    },
+    "version": {
+      "type": "integer"
+    }
+  },
+  "required": [
+    "vault",
+    "category"
+  ],
+  "type": "object"
+}`
+
+// patchRequestSchema is the Patch schema as accepted in request bodies.
+const patchRequestSchema = `{
+  "items": {
+    "properties": {
+      "op": {
+        "enum": [
+          "add",
+          "remove",
+          "replace"
+        ],
+        "type": "string"
+      },
+      "path": {
+        "description": "An RFC6901 JSON Pointer pointing to the Item document, an Item Attribute, and Item Field by Field ID, or an Item Field Attribute",
+        "examples": [
+          "/fields/06gnn2b95example10q91512p5/label"
+        ],
+        "type": "string"
+      },
+      "value": {
+        "description": "The value to add or replace with, which may be any JSON value"
+      }
+    },
+    "required": [
+      "op",
+      "path"
+    ],
+    "type": "object"
+  },
+  "type": "array"
+}`
@@ -0,0 +1,102 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/1password-connect/mcp-server/config"
+	"github.com/1password-connect/mcp-server/connect"
+	"github.com/1password-connect/mcp-server/models"
+	"github.com/1password-connect/mcp-server/redact"
+	"github.com/1password-connect/mcp-server/secretref"
+	"github.com/1password-connect/mcp-server/tools/common"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func SetitemfieldHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	defaultClient := connect.NewClient(cfg)
+	policy := redact.New(cfg)
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		client := connect.ClientFor(ctx, defaultClient)
+		args, ok := request.Params.Arguments.(map[string]any)
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments object"), nil
+		}
+		var desired models.Field
+		val, hasValue := args["value"]
+		if hasValue && val != nil {
+			if desired.Value, ok = val.(string); !ok {
+				return mcp.NewToolResultError("Invalid parameter: value"), nil
+			}
+		}
+		if val, ok := args["generate"]; ok && val != nil {
+			if desired.Generate, ok = val.(bool); !ok {
+				return mcp.NewToolResultError("Invalid parameter: generate"), nil
+			}
+		}
+		if _, ok := args["recipe"]; ok {
+			if !desired.Generate {
+				return mcp.NewToolResultError("Invalid parameter: recipe is only used with generate: true"), nil
+			}
+			if err := common.DecodeBodyArg(args, "recipe", &desired.Recipe); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+		}
+		if hasValue == desired.Generate {
+			return mcp.NewToolResultError("Pass either a value or generate: true"), nil
+		}
+
+		target, result := fetchField(ctx, client, args)
+		if result != nil {
+			return result, nil
+		}
+		field := target.Field
+		patch := models.FieldPatch(field, desired)
+		if hasValue && desired.Value == "" && field.Value != "" {
+			// FieldPatch takes an empty value to leave the value as it is.
+			patch = models.Patch{{Op: models.PatchReplace, Path: "/fields/" + field.Id + "/value", Value: ""}}
+			if cfg.ConfirmDestructive {
+				if result := confirmOperation(ctx, client, args, target.VaultID, target.ItemID, func(vault *models.Vault, item *models.FullItem) string {
+					return describePatchRemovals(vault, item, patchRemovals(patch, item))
+				}); result != nil {
+					return result, nil
+				}
+			}
+		}
+		if len(patch) > 0 {
+			if idempotentPatch(patch) {
+				ctx = connect.WithIdempotentPatch(ctx)
+			}
+			var item models.FullItem
+			if _, err := client.Patch(ctx, connect.Path("vaults", target.VaultID, "items", target.ItemID), patch, &item); err != nil {
+				return common.ErrorResult(err), nil
+			}
+			updated, err := secretref.MatchField(item.Fields, field.Id)
+			if err != nil {
+				return common.ErrorResult(err), nil
+			}
+			field = *updated
+		}
+		policy.Field(&field, common.Reveal(args))
+		return common.JSONResult(field), nil
+	}
+}
+
+func CreateSetitemfieldTool(cfg *config.APIConfig) models.Tool {
+	tool := common.NewTool("set_item_field", "Set item field", http.MethodPatch,
+		mcp.WithDescription("Set the value of a single field of an Item, addressed as in get_item_field, with a JSON Patch that leaves the rest of the Item as it is. Pass an empty value to clear the field, or generate: true, optionally with a recipe, instead of a value to have Connect generate a new secure value. Returns only the updated field"),
+		common.WithOutputSchema[models.Field](),
+		withFieldSelector(),
+		mcp.WithString("value", mcp.Description("The new value of the field; an empty string clears it")),
+		mcp.WithBoolean("generate", mcp.Description("Generate a new value instead of setting one")),
+		withFieldProperty("recipe", "The character sets and length of the generated value; Connect's defaults apply when it is left out"),
+		common.WithReveal(),
+		common.WithConfirm(cfg),
+	)
+
+	return models.Tool{
+		Definition: tool,
+		Handler:    SetitemfieldHandler(cfg),
+		Timeout:    confirmTimeout(cfg),
+	}
+}
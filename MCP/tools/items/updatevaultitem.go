@@ -2,121 +2,75 @@ package tools
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"bytes"
+	"slices"
 
 	"github.com/1password-connect/mcp-server/config"
+	"github.com/1password-connect/mcp-server/connect"
 	"github.com/1password-connect/mcp-server/models"
+	"github.com/1password-connect/mcp-server/redact"
+	"github.com/1password-connect/mcp-server/tools/common"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
 func UpdatevaultitemHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	defaultClient := connect.NewClient(cfg)
+	policy := redact.New(cfg)
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		client := connect.ClientFor(ctx, defaultClient)
 		args, ok := request.Params.Arguments.(map[string]any)
 		if !ok {
 			return mcp.NewToolResultError("Invalid arguments object"), nil
 		}
-		vaultUuidVal, ok := args["vaultUuid"]
-		if !ok {
-			return mcp.NewToolResultError("Missing required path parameter: vaultUuid"), nil
-		}
-		vaultUuid, ok := vaultUuidVal.(string)
-		if !ok {
-			return mcp.NewToolResultError("Invalid path parameter: vaultUuid"), nil
-		}
-		itemUuidVal, ok := args["itemUuid"]
-		if !ok {
-			return mcp.NewToolResultError("Missing required path parameter: itemUuid"), nil
-		}
-		itemUuid, ok := itemUuidVal.(string)
-		if !ok {
-			return mcp.NewToolResultError("Invalid path parameter: itemUuid"), nil
-		}
-		// Create properly typed request body using the generated schema
-		var requestBody models.FullItem
-		
-		// Optimized: Single marshal/unmarshal with JSON tags handling field mapping
-		if argsJSON, err := json.Marshal(args); err == nil {
-			if err := json.Unmarshal(argsJSON, &requestBody); err != nil {
-				return mcp.NewToolResultError(fmt.Sprintf("Failed to convert arguments to request type: %v", err)), nil
-			}
-		} else {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal arguments: %v", err)), nil
+		params, err := parseUpdateVaultItemParams(args)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
 		}
-		
-		bodyBytes, err := json.Marshal(requestBody)
+		vaultUuid, err := client.ResolveVault(ctx, params.VaultUuid)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to encode request body", err), nil
+			return common.ErrorResult(err), nil
 		}
-		url := fmt.Sprintf("%s/vaults/%s/items/%s", cfg.BaseURL, vaultUuid, itemUuid)
-		req, err := http.NewRequest("PUT", url, bytes.NewBuffer(bodyBytes))
-		req.Header.Set("Content-Type", "application/json")
+		itemUuid, err := client.ResolveItem(ctx, vaultUuid, params.ItemUuid)
 		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to create request", err), nil
+			return common.ErrorResult(err), nil
 		}
-		// Set authentication based on auth type
-		if cfg.BearerToken != "" {
-			req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", cfg.BearerToken))
+		requestBody := params.Body
+		if requestBody.Vault == nil {
+			requestBody.Vault = map[string]interface{}{"id": vaultUuid}
 		}
-		req.Header.Set("Accept", "application/json")
-
-		resp, err := http.DefaultClient.Do(req)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Request failed", err), nil
+		if cfg.ConfirmDestructive {
+			describe := func(vault *models.Vault, item *models.FullItem) string {
+				return describeReplace(vault, item, &requestBody)
+			}
+			if result := confirmOperation(ctx, client, args, vaultUuid, itemUuid, describe); result != nil {
+				return result, nil
+			}
 		}
-		defer resp.Body.Close()
 
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to read response body", err), nil
+		// Replacing an item is idempotent unless Connect generates values for
+		// it, which would change with every attempt.
+		if slices.ContainsFunc(requestBody.Fields, func(f models.Field) bool { return f.Generate }) {
+			ctx = connect.WithoutRetries(ctx)
 		}
 
-		if resp.StatusCode >= 400 {
-			return mcp.NewToolResultError(fmt.Sprintf("API error: %s", body)), nil
-		}
-		// Use properly typed response
 		var result models.FullItem
-		if err := json.Unmarshal(body, &result); err != nil {
-			// Fallback to raw text if unmarshaling fails
-			return mcp.NewToolResultText(string(body)), nil
+		if _, err := client.Put(ctx, connect.Path("vaults", vaultUuid, "items", itemUuid), requestBody, &result); err != nil {
+			return common.ErrorResult(err), nil
 		}
-
-		prettyJSON, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultErrorFromErr("Failed to format JSON", err), nil
-		}
-
-		return mcp.NewToolResultText(string(prettyJSON)), nil
+		policy.Item(&result, common.Reveal(args))
+		return common.JSONResult(result), nil
 	}
 }
 
 func CreateUpdatevaultitemTool(cfg *config.APIConfig) models.Tool {
-	tool := mcp.NewTool("put_vaults_vaultUuid_items_itemUuid",
-		mcp.WithDescription("Update an Item"),
-		mcp.WithString("vaultUuid", mcp.Required(), mcp.Description("The UUID of the Item's Vault")),
-		mcp.WithString("itemUuid", mcp.Required(), mcp.Description("The UUID of the Item to update")),
-		mcp.WithString("state", mcp.Description("")),
-		mcp.WithArray("tags", mcp.Description("")),
-		mcp.WithString("title", mcp.Description("")),
-		mcp.WithBoolean("favorite", mcp.Description("")),
-		mcp.WithArray("urls", mcp.Description("")),
-		mcp.WithString("createdAt", mcp.Description("")),
-		mcp.WithString("id", mcp.Description("")),
-		mcp.WithObject("vault", mcp.Description("")),
-		mcp.WithNumber("version", mcp.Description("")),
-		mcp.WithString("lastEditedBy", mcp.Description("")),
-		mcp.WithString("updatedAt", mcp.Description("")),
-		mcp.WithString("category", mcp.Description("")),
-		mcp.WithArray("fields", mcp.Description("")),
-		mcp.WithArray("files", mcp.Description("")),
-		mcp.WithArray("sections", mcp.Description("")),
+	tool := updateVaultItemTool(
+		common.WithOutputSchema[models.FullItem](),
+		common.WithReveal(),
+		common.WithConfirm(cfg),
 	)
 
 	return models.Tool{
 		Definition: tool,
 		Handler:    UpdatevaultitemHandler(cfg),
+		Timeout:    confirmTimeout(cfg),
 	}
 }
@@ -0,0 +1,44 @@
+// Code generated by openapigen from openapi.yaml. DO NOT EDIT.
+
+package tools
+
+import (
+	"net/http"
+
+	"github.com/1password-connect/mcp-server/models"
+	"github.com/1password-connect/mcp-server/tools/common"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// updateVaultItemParams holds the arguments of the put_vaults_vaultUuid_items_itemUuid tool.
+type updateVaultItemParams struct {
+	VaultUuid string // The UUID or name of the Item's Vault
+	ItemUuid  string // The UUID or name of the Item to update
+	Body      models.FullItem
+}
+
+// parseUpdateVaultItemParams reads the arguments of the put_vaults_vaultUuid_items_itemUuid tool.
+func parseUpdateVaultItemParams(args map[string]any) (updateVaultItemParams, error) {
+	var p updateVaultItemParams
+	var err error
+	if p.VaultUuid, err = common.PathParam(args, "vaultUuid"); err != nil {
+		return p, err
+	}
+	if p.ItemUuid, err = common.PathParam(args, "itemUuid"); err != nil {
+		return p, err
+	}
+	if err := common.DecodeBody(args, &p.Body); err != nil {
+		return p, err
+	}
+	return p, nil
+}
+
+// updateVaultItemTool returns the definition of the put_vaults_vaultUuid_items_itemUuid tool, followed by opts.
+func updateVaultItemTool(opts ...mcp.ToolOption) mcp.Tool {
+	return common.NewTool("put_vaults_vaultUuid_items_itemUuid", "Replace item", http.MethodPut, append([]mcp.ToolOption{
+		mcp.WithDescription("Update an Item, replacing it as a whole. `vault` may be left out; it defaults to the Vault given by vaultUuid."),
+		mcp.WithString("vaultUuid", mcp.Required(), mcp.Description("The UUID or name of the Item's Vault")),
+		mcp.WithString("itemUuid", mcp.Required(), mcp.Description("The UUID or name of the Item to update")),
+		common.WithBody(fullItemRequestSchema, "vault"),
+	}, opts...)...)
+}
@@ -0,0 +1,17 @@
+// Code generated by openapigen from openapi.yaml. DO NOT EDIT.
+
+package tools
+
+import (
+	"net/http"
+
+	"github.com/1password-connect/mcp-server/tools/common"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// getPrometheusMetricsTool returns the definition of the get_metrics tool, followed by opts.
+func getPrometheusMetricsTool(opts ...mcp.ToolOption) mcp.Tool {
+	return common.NewTool("get_metrics", "Get Prometheus metrics", http.MethodGet, append([]mcp.ToolOption{
+		mcp.WithDescription("Query server for exposed Prometheus metrics\n\nSee Prometheus documentation for a complete data model."),
+	}, opts...)...)
+}
@@ -0,0 +1,68 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/1password-connect/mcp-server/config"
+	"github.com/1password-connect/mcp-server/connect"
+	"github.com/1password-connect/mcp-server/models"
+	"github.com/1password-connect/mcp-server/redact"
+	"github.com/1password-connect/mcp-server/secretref"
+	"github.com/1password-connect/mcp-server/tools/common"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func InjectsecretreferencesHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	defaultClient := connect.NewClient(cfg)
+	policy := redact.New(cfg)
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		client := connect.ClientFor(ctx, defaultClient)
+		args, ok := request.Params.Arguments.(map[string]any)
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments object"), nil
+		}
+		templateVal, ok := args["template"]
+		if !ok {
+			return mcp.NewToolResultError("Missing required parameter: template"), nil
+		}
+		template, ok := templateVal.(string)
+		if !ok {
+			return mcp.NewToolResultError("Invalid parameter: template"), nil
+		}
+		opts := secretref.InjectOptions{Policy: policy, Reveal: common.Reveal(args)}
+		if val, ok := args["strict"]; ok {
+			if opts.Strict, ok = val.(bool); !ok {
+				return mcp.NewToolResultError("Invalid parameter: strict"), nil
+			}
+		}
+		if val, ok := args["dryRun"]; ok {
+			if opts.DryRun, ok = val.(bool); !ok {
+				return mcp.NewToolResultError("Invalid parameter: dryRun"), nil
+			}
+		}
+		result, err := secretref.Inject(ctx, client, template, opts)
+		if err != nil {
+			return common.ErrorResult(err), nil
+		}
+		return common.JSONResult(result), nil
+	}
+}
+
+func CreateInjectsecretreferencesTool(cfg *config.APIConfig) models.Tool {
+	tool := common.NewTool("inject_secret_references", "Inject secret references", http.MethodGet,
+		mcp.WithDescription("Render a template by replacing every {{ op://vault/item[/section]/field }} placeholder with the value it refers to. Returns the rendered text, the distinct references found and any that could not be resolved, which are left in place"),
+		common.WithOutputSchema[secretref.InjectResult](),
+		mcp.WithString("template", mcp.Required(), mcp.Description("Text containing {{ op://... }} placeholders")),
+		mcp.WithBoolean("strict", mcp.Description("Fail the call if any reference cannot be resolved")),
+		mcp.WithBoolean("dryRun", mcp.Description("Only list the references in the template without fetching any values")),
+		common.WithReveal(),
+	)
+
+	return models.Tool{
+		Definition: tool,
+		Handler:    InjectsecretreferencesHandler(cfg),
+		Timeout:    2 * time.Minute,
+	}
+}
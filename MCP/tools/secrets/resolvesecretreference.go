@@ -0,0 +1,74 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/1password-connect/mcp-server/config"
+	"github.com/1password-connect/mcp-server/connect"
+	"github.com/1password-connect/mcp-server/models"
+	"github.com/1password-connect/mcp-server/redact"
+	"github.com/1password-connect/mcp-server/secretref"
+	"github.com/1password-connect/mcp-server/tools/common"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// resolvedReference is the structured result of resolve_secret_reference.
+type resolvedReference struct {
+	Reference string `json:"reference"`
+	Value     string `json:"value"`
+}
+
+func ResolvesecretreferenceHandler(cfg *config.APIConfig) func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	defaultClient := connect.NewClient(cfg)
+	policy := redact.New(cfg)
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		client := connect.ClientFor(ctx, defaultClient)
+		args, ok := request.Params.Arguments.(map[string]any)
+		if !ok {
+			return mcp.NewToolResultError("Invalid arguments object"), nil
+		}
+		referenceVal, ok := args["reference"]
+		if !ok {
+			return mcp.NewToolResultError("Missing required parameter: reference"), nil
+		}
+		reference, ok := referenceVal.(string)
+		if !ok {
+			return mcp.NewToolResultError("Invalid parameter: reference"), nil
+		}
+		ref, err := secretref.Parse(reference)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		item, err := ref.FetchItem(ctx, client)
+		if err != nil {
+			return common.ErrorResult(err), nil
+		}
+		field, err := ref.FindField(item)
+		if err != nil {
+			return common.ErrorResult(err), nil
+		}
+		if ref.Secret() && policy.MasksField(*field, common.Reveal(args)) {
+			return common.ErrorResult(&redact.Error{What: "The value of " + reference, Mode: policy.Mode}), nil
+		}
+		value, err := ref.Select(item)
+		if err != nil {
+			return common.ErrorResult(err), nil
+		}
+		return mcp.NewToolResultStructured(resolvedReference{Reference: reference, Value: value}, value), nil
+	}
+}
+
+func CreateResolvesecretreferenceTool(cfg *config.APIConfig) models.Tool {
+	tool := common.NewTool("resolve_secret_reference", "Resolve secret reference", http.MethodGet,
+		mcp.WithDescription("Resolve a secret reference of the form op://vault/item[/section]/field to the field's value. Vaults and items may be given by name or UUID, sections and fields by label or ID. Append ?attribute=otp for the current one-time password, or ?attribute=type, id, label or purpose for other field attributes"),
+		common.WithOutputSchema[resolvedReference](),
+		mcp.WithString("reference", mcp.Required(), mcp.Description("The secret reference, e.g. op://Production/Database/password")),
+		common.WithReveal(),
+	)
+
+	return models.Tool{
+		Definition: tool,
+		Handler:    ResolvesecretreferenceHandler(cfg),
+	}
+}
@@ -0,0 +1,33 @@
+// Code generated by openapigen from openapi.yaml. DO NOT EDIT.
+
+package tools
+
+import (
+	"net/http"
+
+	"github.com/1password-connect/mcp-server/tools/common"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// getVaultByIdParams holds the arguments of the get_vaults_vaultUuid tool.
+type getVaultByIdParams struct {
+	VaultUuid string // The UUID or name of the Vault to fetch Items from
+}
+
+// parseGetVaultByIdParams reads the arguments of the get_vaults_vaultUuid tool.
+func parseGetVaultByIdParams(args map[string]any) (getVaultByIdParams, error) {
+	var p getVaultByIdParams
+	var err error
+	if p.VaultUuid, err = common.PathParam(args, "vaultUuid"); err != nil {
+		return p, err
+	}
+	return p, nil
+}
+
+// getVaultByIdTool returns the definition of the get_vaults_vaultUuid tool, followed by opts.
+func getVaultByIdTool(opts ...mcp.ToolOption) mcp.Tool {
+	return common.NewTool("get_vaults_vaultUuid", "Get vault", http.MethodGet, append([]mcp.ToolOption{
+		mcp.WithDescription("Get Vault details and metadata"),
+		mcp.WithString("vaultUuid", mcp.Required(), mcp.Description("The UUID or name of the Vault to fetch Items from")),
+	}, opts...)...)
+}
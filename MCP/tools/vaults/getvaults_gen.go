@@ -0,0 +1,43 @@
+// Code generated by openapigen from openapi.yaml. DO NOT EDIT.
+
+package tools
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/1password-connect/mcp-server/tools/common"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// getVaultsParams holds the arguments of the get_vaults tool.
+type getVaultsParams struct {
+	Filter *string // Filter the Vault collection based on Vault name using SCIM eq filter
+}
+
+// parseGetVaultsParams reads the arguments of the get_vaults tool.
+func parseGetVaultsParams(args map[string]any) (getVaultsParams, error) {
+	var p getVaultsParams
+	var err error
+	if p.Filter, err = common.QueryString(args, "filter"); err != nil {
+		return p, err
+	}
+	return p, nil
+}
+
+// query returns the query parameters that are set.
+func (p getVaultsParams) query() url.Values {
+	query := url.Values{}
+	if p.Filter != nil {
+		query.Set("filter", *p.Filter)
+	}
+	return query
+}
+
+// getVaultsTool returns the definition of the get_vaults tool, followed by opts.
+func getVaultsTool(opts ...mcp.ToolOption) mcp.Tool {
+	return common.NewTool("get_vaults", "List vaults", http.MethodGet, append([]mcp.ToolOption{
+		mcp.WithDescription("Get all Vaults"),
+		mcp.WithString("filter", mcp.Description("Filter the Vault collection based on Vault name using SCIM eq filter")),
+	}, opts...)...)
+}
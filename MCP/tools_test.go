@@ -0,0 +1,1473 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/1password-connect/mcp-server/config"
+	"github.com/1password-connect/mcp-server/connect"
+	"github.com/1password-connect/mcp-server/connect/connecttest"
+	"github.com/1password-connect/mcp-server/models"
+	"github.com/1password-connect/mcp-server/policy"
+	"github.com/1password-connect/mcp-server/redact"
+	"github.com/1password-connect/mcp-server/secretref"
+	"github.com/1password-connect/mcp-server/session"
+	"github.com/google/jsonschema-go/jsonschema"
+	mcpclient "github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// fixture is a fake Connect server seeded with a vault holding a login with
+// a TOTP field and two files, and a secure note.
+type fixture struct {
+	connect *connecttest.Server
+	vault   models.Vault
+	login   models.FullItem
+	note    models.FullItem
+	text    models.File
+	binary  models.File
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+	return seedFixture(t, connecttest.NewServer())
+}
+
+// seedFixture seeds the started server fake and closes it when the test
+// ends.
+func seedFixture(t *testing.T, fake *connecttest.Server) *fixture {
+	t.Helper()
+	f := &fixture{connect: fake}
+	t.Cleanup(f.connect.Close)
+	store := f.connect.Store
+
+	f.vault = store.AddVault("Production", "Shared production credentials")
+	store.AddVault("Staging", "")
+	var err error
+	f.login, err = store.AddItem(f.vault.Id, models.FullItem{
+		Title:    "Database",
+		Category: "LOGIN",
+		Tags:     []string{"db"},
+		Sections: []map[string]interface{}{{"id": "admin", "label": "Admin"}},
+		Fields: []models.Field{
+			{Id: "username", Label: "username", Purpose: "USERNAME", TypeField: "STRING", Value: "postgres"},
+			{Id: "password", Label: "password", Purpose: "PASSWORD", TypeField: "CONCEALED", Value: "hunter2"},
+			{Id: "otp", Label: "one-time password", TypeField: "TOTP", Value: "otpauth://totp/db?secret=JBSWY3DPEHPK3PXP"},
+			{Id: "pin", Label: "pin", TypeField: "CONCEALED", Value: "1234", Section: map[string]interface{}{"id": "admin"}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.note, err = store.AddItem(f.vault.Id, models.FullItem{Title: "Runbook", Category: "SECURE_NOTE"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.text, err = store.AddFile(f.vault.Id, f.login.Id, "config.txt", []byte("host=db.internal\n")); err != nil {
+		t.Fatal(err)
+	}
+	if f.binary, err = store.AddFile(f.vault.Id, f.login.Id, "dump.bin", []byte{0x00, 0xff, 0x10, 0x80}); err != nil {
+		t.Fatal(err)
+	}
+	// Read the login, with its files, the way a client would, which also
+	// starts the activity log.
+	if _, err := connect.NewClient(f.connect.APIConfig()).Get(context.Background(), connect.Path("vaults", f.vault.Id, "items", f.login.Id), nil, &f.login); err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+// transports starts MCP servers for cfg over each transport and returns a
+// connected client, created with opts, by transport name.
+var transports = map[string]func(t *testing.T, cfg *config.APIConfig, opts ...mcpclient.ClientOption) *mcpclient.Client{
+	"STDIO": stdioClient,
+	"HTTP":  httpClient,
+	"SSE":   sseClient,
+}
+
+// stdioClient serves cfg over the STDIO transport through in-memory pipes,
+// listing the vaults up front the way main does.
+func stdioClient(t *testing.T, cfg *config.APIConfig, opts ...mcpclient.ClientOption) *mcpclient.Client {
+	mcpSrv, registry := createMCPServer(cfg, "STDIO", &server.Hooks{})
+	ctx, cancel := context.WithCancel(context.Background())
+	registry.SyncVaults(ctx)
+	go registry.Watch(ctx)
+	clientIn, serverOut := io.Pipe()
+	serverIn, clientOut := io.Pipe()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		server.NewStdioServer(mcpSrv).Listen(ctx, serverIn, serverOut)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		serverIn.Close()
+		serverOut.Close()
+		<-done
+	})
+	return startClient(t, mcpclient.NewClient(transport.NewIO(clientIn, clientOut, io.NopCloser(strings.NewReader(""))), opts...))
+}
+
+// httpClient serves cfg over the Streamable HTTP transport, passing its
+// credentials as headers and listening for notifications the way HTTP
+// clients do.
+func httpClient(t *testing.T, cfg *config.APIConfig, opts ...mcpclient.ClientOption) *mcpclient.Client {
+	serverCfg := *cfg
+	serverCfg.BaseURL, serverCfg.BearerToken = "", ""
+	mcpSrv, registry := createMCPServer(&serverCfg, "HTTP", &server.Hooks{})
+	ctx, cancel := context.WithCancel(context.Background())
+	go registry.Watch(ctx)
+	httpSrv := httptest.NewServer(streamableHandler(mcpSrv, &serverCfg, session.NewManager(time.Minute)))
+	t.Cleanup(func() {
+		cancel()
+		httpSrv.Close()
+	})
+	trans, err := transport.NewStreamableHTTP(httpSrv.URL, transport.WithContinuousListening(), transport.WithHTTPHeaders(map[string]string{
+		"API_BASE_URL": cfg.BaseURL,
+		"BEARER_TOKEN": cfg.BearerToken,
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return startClient(t, mcpclient.NewClient(trans, opts...))
+}
+
+// sseClient serves cfg over the legacy HTTP+SSE transport, passing its
+// credentials as headers of the SSE stream.
+func sseClient(t *testing.T, cfg *config.APIConfig, opts ...mcpclient.ClientOption) *mcpclient.Client {
+	trans, err := transport.NewSSE(serveSSE(t, cfg)+"/sse", transport.WithHeaders(map[string]string{
+		"API_BASE_URL": cfg.BaseURL,
+		"BEARER_TOKEN": cfg.BearerToken,
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return startClient(t, mcpclient.NewClient(trans, opts...))
+}
+
+// serveSSE starts the /sse and /message handlers for cfg the way main does
+// and returns the server's URL.
+func serveSSE(t *testing.T, cfg *config.APIConfig) string {
+	serverCfg := *cfg
+	serverCfg.BaseURL, serverCfg.BearerToken = "", ""
+	hooks := &server.Hooks{}
+	credentials := newSSECredentials()
+	credentials.register(hooks)
+	mcpSrv, registry := createMCPServer(&serverCfg, "SSE", hooks)
+	ctx, cancel := context.WithCancel(context.Background())
+	go registry.Watch(ctx)
+	sse, message := credentials.handlers(mcpSrv, &serverCfg)
+	mux := http.NewServeMux()
+	mux.Handle("/sse", sse)
+	mux.Handle("/message", message)
+	httpSrv := httptest.NewServer(mux)
+	t.Cleanup(func() {
+		cancel()
+		httpSrv.Close()
+	})
+	return httpSrv.URL
+}
+
+func startClient(t *testing.T, c *mcpclient.Client) *mcpclient.Client {
+	t.Helper()
+	ctx := context.Background()
+	if err := c.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.Close() })
+	initRequest := mcp.InitializeRequest{}
+	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	initRequest.Params.ClientInfo = mcp.Implementation{Name: "tools_test", Version: "1.0.0"}
+	if _, err := c.Initialize(ctx, initRequest); err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func callTool(t *testing.T, c *mcpclient.Client, name string, args map[string]any) *mcp.CallToolResult {
+	t.Helper()
+	request := mcp.CallToolRequest{}
+	request.Params.Name = name
+	if args == nil {
+		args = map[string]any{}
+	}
+	request.Params.Arguments = args
+	result, err := c.CallTool(context.Background(), request)
+	if err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	return result
+}
+
+// text returns the text content of a tool result.
+func text(result *mcp.CallToolResult) string {
+	var parts []string
+	for _, content := range result.Content {
+		if tc, ok := content.(mcp.TextContent); ok {
+			parts = append(parts, tc.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// structured decodes the structured content of a tool result into v.
+func structured(t *testing.T, result *mcp.CallToolResult, v any) {
+	t.Helper()
+	data, err := json.Marshal(result.StructuredContent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		t.Fatalf("decoding %s: %v", data, err)
+	}
+}
+
+func succeeded(t *testing.T, result *mcp.CallToolResult) {
+	t.Helper()
+	if result.IsError {
+		t.Fatalf("tool call failed: %s", text(result))
+	}
+}
+
+// resolveSchema compiles the output schema a tool declares.
+func resolveSchema(t *testing.T, declared mcp.ToolOutputSchema) *jsonschema.Resolved {
+	t.Helper()
+	data, err := json.Marshal(declared)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var schema jsonschema.Schema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatal(err)
+	}
+	resolved, err := schema.Resolve(nil)
+	if err != nil {
+		t.Fatalf("output schema %s: %v", data, err)
+	}
+	return resolved
+}
+
+// checkOutputSchema checks that a successful result's structured content
+// validates against the tool's output schema.
+func checkOutputSchema(t *testing.T, schema *jsonschema.Resolved, result *mcp.CallToolResult) {
+	t.Helper()
+	if result.StructuredContent == nil {
+		t.Fatal("tool declares an output schema but returned no structured content")
+	}
+	var instance any
+	structured(t, result, &instance)
+	if err := schema.Validate(instance); err != nil {
+		t.Errorf("structured content does not match the output schema: %v", err)
+	}
+}
+
+// toolCase calls a tool against a fresh fixture and checks the outcome.
+type toolCase struct {
+	tool  string
+	args  func(f *fixture) map[string]any
+	check func(t *testing.T, f *fixture, result *mcp.CallToolResult)
+}
+
+var toolCases = []toolCase{
+	{
+		tool: "get_activity",
+		args: func(f *fixture) map[string]any { return map[string]any{"limit": 1} },
+		check: func(t *testing.T, f *fixture, result *mcp.CallToolResult) {
+			succeeded(t, result)
+			var out struct{ Requests []models.APIRequest }
+			structured(t, result, &out)
+			if len(out.Requests) != 1 {
+				t.Fatalf("limit 1 returned %d requests", len(out.Requests))
+			}
+			resource := out.Requests[0].Resource
+			if out.Requests[0].Action != "READ" || resource["type"] != "ITEM" || resource["item"].(map[string]any)["id"] != f.login.Id {
+				t.Errorf("activity = %+v, want the fixture's read of the login", out.Requests[0])
+			}
+		},
+	},
+	{
+		tool: "get_health",
+		check: func(t *testing.T, f *fixture, result *mcp.CallToolResult) {
+			succeeded(t, result)
+			var out struct{ Name, Version string }
+			structured(t, result, &out)
+			if out.Version != connecttest.Version {
+				t.Errorf("version = %q, want %q", out.Version, connecttest.Version)
+			}
+		},
+	},
+	{
+		tool: "get_heartbeat",
+		check: func(t *testing.T, f *fixture, result *mcp.CallToolResult) {
+			succeeded(t, result)
+			if text(result) != "." {
+				t.Errorf("heartbeat = %q, want %q", text(result), ".")
+			}
+		},
+	},
+	{
+		tool: "get_metrics",
+		check: func(t *testing.T, f *fixture, result *mcp.CallToolResult) {
+			succeeded(t, result)
+			if !strings.Contains(text(result), "# TYPE connect_http_requests_total counter") {
+				t.Errorf("metrics lack the request counter:\n%s", text(result))
+			}
+		},
+	},
+	{
+		tool: "get_vaults",
+		args: func(f *fixture) map[string]any { return map[string]any{"filter": `name eq "production"`} },
+		check: func(t *testing.T, f *fixture, result *mcp.CallToolResult) {
+			succeeded(t, result)
+			var out struct{ Vaults []models.Vault }
+			structured(t, result, &out)
+			if len(out.Vaults) != 1 || out.Vaults[0].Id != f.vault.Id || out.Vaults[0].Items != 2 {
+				t.Errorf("vaults = %+v, want only %s with 2 items", out.Vaults, f.vault.Id)
+			}
+		},
+	},
+	{
+		tool: "get_vaults_vaultUuid",
+		args: func(f *fixture) map[string]any { return map[string]any{"vaultUuid": "Production"} },
+		check: func(t *testing.T, f *fixture, result *mcp.CallToolResult) {
+			succeeded(t, result)
+			var vault models.Vault
+			structured(t, result, &vault)
+			if vault.Id != f.vault.Id || vault.Description != f.vault.Description {
+				t.Errorf("vault = %+v, want %+v", vault, f.vault)
+			}
+		},
+	},
+	{
+		tool: "get_vaults_vaultUuid_items",
+		args: func(f *fixture) map[string]any { return map[string]any{"vaultUuid": f.vault.Id} },
+		check: func(t *testing.T, f *fixture, result *mcp.CallToolResult) {
+			succeeded(t, result)
+			var out struct{ Items []models.Item }
+			structured(t, result, &out)
+			var titles []string
+			for _, it := range out.Items {
+				titles = append(titles, it.Title)
+			}
+			if !slices.Equal(titles, []string{"Database", "Runbook"}) {
+				t.Errorf("titles = %q", titles)
+			}
+		},
+	},
+	{
+		tool: "post_vaults_vaultUuid_items",
+		args: func(f *fixture) map[string]any {
+			return map[string]any{
+				"vaultUuid": "Production",
+				"title":     "API key",
+				"category":  "API_CREDENTIAL",
+				"fields": []any{map[string]any{
+					"id": "credential", "type": "CONCEALED", "generate": true,
+					"recipe": map[string]any{"length": 40, "characterSets": []any{"DIGITS"}},
+				}},
+			}
+		},
+		check: func(t *testing.T, f *fixture, result *mcp.CallToolResult) {
+			succeeded(t, result)
+			var created models.FullItem
+			structured(t, result, &created)
+			stored, ok := f.connect.Store.Item(f.vault.Id, created.Id)
+			if !ok {
+				t.Fatalf("item %s was not stored", created.Id)
+			}
+			if stored.Vault["id"] != f.vault.Id || stored.Version != 1 {
+				t.Errorf("stored item is in %v at version %d", stored.Vault, stored.Version)
+			}
+			if value := stored.Fields[0].Value; len(value) != 40 || strings.Trim(value, "0123456789") != "" {
+				t.Errorf("generated value %q does not follow the recipe", value)
+			}
+		},
+	},
+	{
+		tool: "get_vaults_vaultUuid_items_itemUuid",
+		args: func(f *fixture) map[string]any {
+			return map[string]any{"vaultUuid": "Production", "itemUuid": "database", "reveal": true}
+		},
+		check: func(t *testing.T, f *fixture, result *mcp.CallToolResult) {
+			succeeded(t, result)
+			var it models.FullItem
+			structured(t, result, &it)
+			if it.Id != f.login.Id || len(it.Files) != 2 {
+				t.Fatalf("item = %s with %d files, want %s with 2", it.Id, len(it.Files), f.login.Id)
+			}
+			for _, field := range it.Fields {
+				if field.Id == "password" && field.Value != "hunter2" {
+					t.Errorf("revealed password = %q", field.Value)
+				}
+				if field.Id == "otp" && len(field.Totp) != 6 {
+					t.Errorf("totp = %q, want a 6 digit code", field.Totp)
+				}
+			}
+		},
+	},
+	{
+		tool: "patch_vaults_vaultUuid_items_itemUuid",
+		args: func(f *fixture) map[string]any {
+			return map[string]any{
+				"vaultUuid": f.vault.Id,
+				"itemUuid":  f.login.Id,
+				"items": []any{
+					map[string]any{"op": "replace", "path": "/title", "value": "Primary database"},
+					map[string]any{"op": "replace", "path": "/fields/password/value", "value": "correct horse"},
+					map[string]any{"op": "add", "path": "/tags", "value": "prod"},
+					map[string]any{"op": "remove", "path": "/fields/pin"},
+				},
+			}
+		},
+		check: func(t *testing.T, f *fixture, result *mcp.CallToolResult) {
+			succeeded(t, result)
+			stored, _ := f.connect.Store.Item(f.vault.Id, f.login.Id)
+			if stored.Title != "Primary database" || !slices.Equal(stored.Tags, []string{"db", "prod"}) {
+				t.Errorf("patched item has title %q and tags %q", stored.Title, stored.Tags)
+			}
+			if stored.Version != f.login.Version+1 {
+				t.Errorf("version = %d, want %d", stored.Version, f.login.Version+1)
+			}
+			var ids []string
+			for _, field := range stored.Fields {
+				ids = append(ids, field.Id)
+				if field.Id == "password" && field.Value != "correct horse" {
+					t.Errorf("password = %q", field.Value)
+				}
+			}
+			if slices.Contains(ids, "pin") {
+				t.Errorf("field pin was not removed: %q", ids)
+			}
+		},
+	},
+	{
+		tool: "put_vaults_vaultUuid_items_itemUuid",
+		args: func(f *fixture) map[string]any {
+			return map[string]any{
+				"vaultUuid": f.vault.Id,
+				"itemUuid":  "Runbook",
+				"title":     "Incident runbook",
+				"category":  "SECURE_NOTE",
+				"fields":    []any{map[string]any{"id": "notesPlain", "type": "STRING", "purpose": "NOTES", "value": "Page the on-call"}},
+			}
+		},
+		check: func(t *testing.T, f *fixture, result *mcp.CallToolResult) {
+			succeeded(t, result)
+			stored, _ := f.connect.Store.Item(f.vault.Id, f.note.Id)
+			if stored.Title != "Incident runbook" || len(stored.Fields) != 1 || stored.Version != f.note.Version+1 {
+				t.Errorf("replaced item = %+v", stored)
+			}
+		},
+	},
+	{
+		tool: "delete_vaults_vaultUuid_items_itemUuid",
+		args: func(f *fixture) map[string]any { return map[string]any{"vaultUuid": f.vault.Id, "itemUuid": f.note.Id} },
+		check: func(t *testing.T, f *fixture, result *mcp.CallToolResult) {
+			succeeded(t, result)
+			if _, ok := f.connect.Store.Item(f.vault.Id, f.note.Id); ok {
+				t.Error("item still exists")
+			}
+		},
+	},
+	{
+		tool: "get_vaults_vaultUuid_items_itemUuid_files",
+		args: func(f *fixture) map[string]any {
+			return map[string]any{"vaultUuid": f.vault.Id, "itemUuid": f.login.Id, "inline_files": true, "reveal": true}
+		},
+		check: func(t *testing.T, f *fixture, result *mcp.CallToolResult) {
+			succeeded(t, result)
+			var out struct{ Files []models.File }
+			structured(t, result, &out)
+			if len(out.Files) != 2 {
+				t.Fatalf("got %d files, want 2", len(out.Files))
+			}
+			if content, _ := base64.StdEncoding.DecodeString(out.Files[0].Content); string(content) != "host=db.internal\n" {
+				t.Errorf("inlined content = %q", content)
+			}
+		},
+	},
+	{
+		tool: "get_vaults_vaultUuid_items_itemUuid_files_fileUuid",
+		args: func(f *fixture) map[string]any {
+			return map[string]any{"vaultUuid": f.vault.Id, "itemUuid": f.login.Id, "fileUuid": f.binary.Id}
+		},
+		check: func(t *testing.T, f *fixture, result *mcp.CallToolResult) {
+			succeeded(t, result)
+			var file models.File
+			structured(t, result, &file)
+			if file.Name != "dump.bin" || file.Size != 4 || file.Content != "" {
+				t.Errorf("file = %+v", file)
+			}
+		},
+	},
+	{
+		tool: "get_vaults_vaultUuid_items_itemUuid_files_fileUuid_content",
+		args: func(f *fixture) map[string]any {
+			return map[string]any{"vaultUuid": f.vault.Id, "itemUuid": f.login.Id, "fileUuid": f.text.Id, "reveal": true}
+		},
+		check: func(t *testing.T, f *fixture, result *mcp.CallToolResult) {
+			succeeded(t, result)
+			if text(result) != "host=db.internal\n" {
+				t.Errorf("content = %q", text(result))
+			}
+		},
+	},
+	{
+		tool: "get_item_field",
+		args: func(f *fixture) map[string]any {
+			return map[string]any{"vaultUuid": "Production", "itemUuid": "Database", "purpose": "PASSWORD", "reveal": true}
+		},
+		check: func(t *testing.T, f *fixture, result *mcp.CallToolResult) {
+			succeeded(t, result)
+			var field models.Field
+			structured(t, result, &field)
+			if field.Id != "password" || field.Value != "hunter2" {
+				t.Errorf("field %s = %q, want password = %q", field.Id, field.Value, "hunter2")
+			}
+		},
+	},
+	{
+		tool: "set_item_field",
+		args: func(f *fixture) map[string]any {
+			return map[string]any{
+				"vaultUuid": f.vault.Id,
+				"itemUuid":  f.login.Id,
+				"field":     "password",
+				"generate":  true,
+				"recipe":    map[string]any{"length": 20, "characterSets": []any{"DIGITS"}},
+			}
+		},
+		check: func(t *testing.T, f *fixture, result *mcp.CallToolResult) {
+			succeeded(t, result)
+			var field models.Field
+			structured(t, result, &field)
+			if field.Id != "password" || field.Value != redact.Mask {
+				t.Errorf("returned field %s = %q, want the masked password", field.Id, field.Value)
+			}
+			stored, _ := f.connect.Store.Item(f.vault.Id, f.login.Id)
+			if stored.Version != f.login.Version+1 {
+				t.Errorf("version = %d, want %d", stored.Version, f.login.Version+1)
+			}
+			for _, field := range stored.Fields {
+				if field.Id == "password" && (len(field.Value) != 20 || strings.Trim(field.Value, "0123456789") != "") {
+					t.Errorf("generated password = %q, want 20 digits", field.Value)
+				}
+			}
+		},
+	},
+	{
+		tool: "resolve_secret_reference",
+		args: func(f *fixture) map[string]any {
+			return map[string]any{"reference": "op://Production/Database/Admin/pin", "reveal": true}
+		},
+		check: func(t *testing.T, f *fixture, result *mcp.CallToolResult) {
+			succeeded(t, result)
+			if text(result) != "1234" {
+				t.Errorf("value = %q, want %q", text(result), "1234")
+			}
+		},
+	},
+	{
+		tool: "inject_secret_references",
+		args: func(f *fixture) map[string]any {
+			return map[string]any{"template": "postgres://{{ op://Production/Database/username }}@db", "reveal": true}
+		},
+		check: func(t *testing.T, f *fixture, result *mcp.CallToolResult) {
+			succeeded(t, result)
+			var out struct{ Text string }
+			structured(t, result, &out)
+			if out.Text != "postgres://postgres@db" {
+				t.Errorf("text = %q", out.Text)
+			}
+		},
+	},
+}
+
+// TestTools calls every tool the server lists against the fake Connect
+// server, over each transport.
+func TestTools(t *testing.T) {
+	for name, newClient := range transports {
+		t.Run(name, func(t *testing.T) {
+			listed, err := newClient(t, newFixture(t).connect.APIConfig()).ListTools(context.Background(), mcp.ListToolsRequest{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			schemas := make(map[string]*jsonschema.Resolved)
+			for _, tool := range listed.Tools {
+				if !slices.ContainsFunc(toolCases, func(tc toolCase) bool { return tc.tool == tool.Name }) {
+					t.Errorf("tool %s has no test case", tool.Name)
+				}
+				if tool.OutputSchema.Type != "" {
+					schemas[tool.Name] = resolveSchema(t, tool.OutputSchema)
+				}
+			}
+
+			for _, tc := range toolCases {
+				t.Run(tc.tool, func(t *testing.T) {
+					f := newFixture(t)
+					var args map[string]any
+					if tc.args != nil {
+						args = tc.args(f)
+					}
+					result := callTool(t, newClient(t, f.connect.APIConfig()), tc.tool, args)
+					if schema := schemas[tc.tool]; schema != nil && !result.IsError {
+						checkOutputSchema(t, schema, result)
+					}
+					tc.check(t, f, result)
+				})
+			}
+		})
+	}
+}
+
+// TestToolAnnotations checks the behaviour hints every listed tool carries,
+// on which read-only mode relies to leave out mutating tools.
+func TestToolAnnotations(t *testing.T) {
+	type hints struct{ readOnly, destructive, idempotent bool }
+	var (
+		read      = hints{readOnly: true, idempotent: true}
+		create    = hints{}
+		overwrite = hints{destructive: true, idempotent: true}
+		modify    = hints{destructive: true}
+	)
+	want := map[string]hints{
+		"get_activity":                                               read,
+		"get_health":                                                 read,
+		"get_heartbeat":                                              read,
+		"get_metrics":                                                read,
+		"get_vaults":                                                 read,
+		"get_vaults_vaultUuid":                                       read,
+		"get_vaults_vaultUuid_items":                                 read,
+		"post_vaults_vaultUuid_items":                                create,
+		"get_vaults_vaultUuid_items_itemUuid":                        read,
+		"patch_vaults_vaultUuid_items_itemUuid":                      modify,
+		"put_vaults_vaultUuid_items_itemUuid":                        overwrite,
+		"delete_vaults_vaultUuid_items_itemUuid":                     overwrite,
+		"get_vaults_vaultUuid_items_itemUuid_files":                  read,
+		"get_vaults_vaultUuid_items_itemUuid_files_fileUuid":         read,
+		"get_vaults_vaultUuid_items_itemUuid_files_fileUuid_content": read,
+		"get_item_field":                                             read,
+		"set_item_field":                                             modify,
+		"resolve_secret_reference":                                   read,
+		"inject_secret_references":                                   read,
+	}
+	for name, newClient := range transports {
+		t.Run(name, func(t *testing.T) {
+			f := newFixture(t)
+			listed, err := newClient(t, f.connect.APIConfig()).ListTools(context.Background(), mcp.ListToolsRequest{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(listed.Tools) != len(want) {
+				t.Errorf("listed %d tools, want %d", len(listed.Tools), len(want))
+			}
+			for _, tool := range listed.Tools {
+				w, ok := want[tool.Name]
+				if !ok {
+					t.Errorf("no expected hints for %s", tool.Name)
+					continue
+				}
+				a := tool.Annotations
+				if a.ReadOnlyHint == nil || a.DestructiveHint == nil || a.IdempotentHint == nil || a.OpenWorldHint == nil {
+					t.Errorf("%s: annotations %+v are incomplete", tool.Name, a)
+					continue
+				}
+				got := hints{readOnly: *a.ReadOnlyHint, destructive: *a.DestructiveHint, idempotent: *a.IdempotentHint}
+				if got != w || *a.OpenWorldHint || a.Title == "" {
+					t.Errorf("%s: hints = %+v, open world %v, title %q; want %+v, closed world and a title", tool.Name, got, *a.OpenWorldHint, a.Title, w)
+				}
+			}
+		})
+	}
+}
+
+// TestReadOnly checks that a read-only server lists no tool that modifies
+// vaults.
+func TestReadOnly(t *testing.T) {
+	f := newFixture(t)
+	var mutating []string
+	for _, tool := range GetAll(f.connect.APIConfig()) {
+		if !readOnly(tool) {
+			mutating = append(mutating, tool.Definition.Name)
+		}
+	}
+	if !slices.Contains(mutating, "set_item_field") {
+		t.Fatalf("mutating tools = %v, want set_item_field among them", mutating)
+	}
+
+	for name, newClient := range transports {
+		t.Run(name, func(t *testing.T) {
+			cfg := f.connect.APIConfig()
+			cfg.ReadOnly = true
+			listed, err := newClient(t, cfg).ListTools(context.Background(), mcp.ListToolsRequest{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var names []string
+			for _, tool := range listed.Tools {
+				names = append(names, tool.Name)
+			}
+			for _, name := range mutating {
+				if slices.Contains(names, name) {
+					t.Errorf("read-only server lists %s", name)
+				}
+			}
+			if !slices.Contains(names, "get_vaults") {
+				t.Errorf("tools = %v, want get_vaults among them", names)
+			}
+		})
+	}
+}
+
+// TestRedaction checks that REDACTION_MODE=always masks secrets even when a
+// call asks to reveal them, and keeps what describes the fields.
+func TestRedaction(t *testing.T) {
+	for name, newClient := range transports {
+		t.Run(name, func(t *testing.T) {
+			f := newFixture(t)
+			cfg := f.connect.APIConfig()
+			cfg.RedactionMode = config.RedactAlways
+			c := newClient(t, cfg)
+
+			result := callTool(t, c, "get_vaults_vaultUuid_items_itemUuid", map[string]any{"vaultUuid": f.vault.Id, "itemUuid": f.login.Id, "reveal": true})
+			succeeded(t, result)
+			if strings.Contains(text(result), "hunter2") || strings.Contains(text(result), "JBSWY3DPEHPK3PXP") {
+				t.Errorf("result reveals a secret: %s", text(result))
+			}
+			var it models.FullItem
+			structured(t, result, &it)
+			for _, field := range it.Fields {
+				want := redact.Mask
+				if field.Id == "username" {
+					want = "postgres"
+				}
+				if field.Value != want || field.Label == "" {
+					t.Errorf("field %s = %q labelled %q, want %q", field.Id, field.Value, field.Label, want)
+				}
+				if field.Id == "password" && field.Entropy == 0 {
+					t.Error("password entropy was dropped")
+				}
+				if field.Id == "otp" && field.Totp != redact.Mask {
+					t.Errorf("totp = %q, want it masked", field.Totp)
+				}
+			}
+
+			result = callTool(t, c, "get_vaults_vaultUuid_items_itemUuid_files", map[string]any{"vaultUuid": f.vault.Id, "itemUuid": f.login.Id, "inline_files": true, "reveal": true})
+			succeeded(t, result)
+			var out struct{ Files []models.File }
+			structured(t, result, &out)
+			for _, file := range out.Files {
+				if file.Content != redact.Mask || file.Name == "" {
+					t.Errorf("file %s content = %q, want it masked", file.Name, file.Content)
+				}
+			}
+		})
+	}
+}
+
+// TestPolicy checks that vaults outside the policy's scope are hidden from
+// listings and refused when referenced.
+func TestPolicy(t *testing.T) {
+	for name, newClient := range transports {
+		t.Run(name, func(t *testing.T) {
+			f := newFixture(t)
+			// Read a vault the policy will leave out, so that it has activity.
+			hidden := f.connect.Store.AddVault("Development", "")
+			if _, err := connect.NewClient(f.connect.APIConfig()).Get(context.Background(), connect.Path("vaults", hidden.Id), nil, nil); err != nil {
+				t.Fatal(err)
+			}
+			cfg := f.connect.APIConfig()
+			cfg.Policy = &policy.Policy{Vaults: policy.Rule{Allow: []string{"production", "Staging"}}}
+			c := newClient(t, cfg)
+
+			result := callTool(t, c, "get_vaults", nil)
+			succeeded(t, result)
+			var vaults struct{ Vaults []models.Vault }
+			structured(t, result, &vaults)
+			if len(vaults.Vaults) != 2 || slices.ContainsFunc(vaults.Vaults, func(v models.Vault) bool { return v.Id == hidden.Id }) {
+				t.Errorf("vaults = %+v, want Production and Staging", vaults.Vaults)
+			}
+
+			result = callTool(t, c, "get_vaults_vaultUuid", map[string]any{"vaultUuid": hidden.Id})
+			checkToolError(t, result, "policy_denied", 0)
+			result = callTool(t, c, "get_vaults_vaultUuid_items", map[string]any{"vaultUuid": "Development"})
+			checkToolError(t, result, "policy_denied", 0)
+
+			result = callTool(t, c, "get_activity", nil)
+			succeeded(t, result)
+			var activity struct{ Requests []models.APIRequest }
+			structured(t, result, &activity)
+			for _, r := range activity.Requests {
+				if vault, _ := r.Resource["vault"].(map[string]any); vault["id"] == hidden.Id {
+					t.Errorf("activity lists %+v from the hidden vault", r)
+				}
+			}
+			if len(activity.Requests) == 0 {
+				t.Error("activity is empty, want the requests to allowed vaults")
+			}
+		})
+	}
+}
+
+// elicitor answers every elicitation request with action and records the
+// messages it was asked.
+type elicitor struct {
+	action   mcp.ElicitationResponseAction
+	mu       sync.Mutex
+	messages []string
+}
+
+func (e *elicitor) Elicit(ctx context.Context, request mcp.ElicitationRequest) (*mcp.ElicitationResult, error) {
+	e.mu.Lock()
+	e.messages = append(e.messages, request.Params.Message)
+	e.mu.Unlock()
+	result := &mcp.ElicitationResult{}
+	result.Action = e.action
+	if e.action == mcp.ElicitationResponseActionAccept {
+		result.Content = map[string]any{"confirm": true}
+	}
+	return result, nil
+}
+
+func (e *elicitor) asked() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return slices.Clone(e.messages)
+}
+
+// TestConfirmDestructive checks that destructive calls are only carried out
+// once the user confirms them, through elicitation or the confirm argument.
+func TestConfirmDestructive(t *testing.T) {
+	for name, newClient := range transports {
+		t.Run(name, func(t *testing.T) {
+			newConfirmingClient := func(t *testing.T, f *fixture, opts ...mcpclient.ClientOption) *mcpclient.Client {
+				cfg := f.connect.APIConfig()
+				cfg.ConfirmDestructive = true
+				return newClient(t, cfg, opts...)
+			}
+			deleteNote := func(f *fixture, confirm bool) map[string]any {
+				args := map[string]any{"vaultUuid": f.vault.Id, "itemUuid": f.note.Id}
+				if confirm {
+					args["confirm"] = true
+				}
+				return args
+			}
+			noteExists := func(f *fixture) bool {
+				_, ok := f.connect.Store.Item(f.vault.Id, f.note.Id)
+				return ok
+			}
+
+			t.Run("confirm argument", func(t *testing.T) {
+				f := newFixture(t)
+				c := newConfirmingClient(t, f)
+				result := callTool(t, c, "delete_vaults_vaultUuid_items_itemUuid", deleteNote(f, false))
+				checkToolError(t, result, "confirmation_required", 0)
+				if !noteExists(f) || !strings.Contains(text(result), `"Runbook"`) {
+					t.Fatalf("unconfirmed delete: %s", text(result))
+				}
+				succeeded(t, callTool(t, c, "delete_vaults_vaultUuid_items_itemUuid", deleteNote(f, true)))
+				if noteExists(f) {
+					t.Error("confirmed delete left the item")
+				}
+			})
+
+			t.Run("elicitation without a channel", func(t *testing.T) {
+				if name != "SSE" {
+					t.Skip("the transport can send elicitation requests")
+				}
+				// SSE sessions cannot send requests to the client, so
+				// even clients that can elicit confirm with the argument.
+				f := newFixture(t)
+				user := &elicitor{action: mcp.ElicitationResponseActionAccept}
+				c := newConfirmingClient(t, f, mcpclient.WithElicitationHandler(user))
+				checkToolError(t, callTool(t, c, "delete_vaults_vaultUuid_items_itemUuid", deleteNote(f, false)), "confirmation_required", 0)
+				succeeded(t, callTool(t, c, "delete_vaults_vaultUuid_items_itemUuid", deleteNote(f, true)))
+				if asked := user.asked(); len(asked) != 0 {
+					t.Errorf("user was asked %q over SSE", asked)
+				}
+			})
+
+			t.Run("elicitation accepted", func(t *testing.T) {
+				if name == "SSE" {
+					t.Skip("SSE sessions cannot send elicitation requests")
+				}
+				f := newFixture(t)
+				user := &elicitor{action: mcp.ElicitationResponseActionAccept}
+				c := newConfirmingClient(t, f, mcpclient.WithElicitationHandler(user))
+				succeeded(t, callTool(t, c, "delete_vaults_vaultUuid_items_itemUuid", deleteNote(f, false)))
+				if asked := user.asked(); len(asked) != 1 || !strings.Contains(asked[0], `"Runbook"`) {
+					t.Errorf("user was asked %q, want one question naming the item", asked)
+				}
+				if noteExists(f) {
+					t.Error("accepted delete left the item")
+				}
+			})
+
+			t.Run("elicitation declined", func(t *testing.T) {
+				if name == "SSE" {
+					t.Skip("SSE sessions cannot send elicitation requests")
+				}
+				f := newFixture(t)
+				user := &elicitor{action: mcp.ElicitationResponseActionDecline}
+				c := newConfirmingClient(t, f, mcpclient.WithElicitationHandler(user))
+				// The confirm argument does not stand in for the user's answer.
+				result := callTool(t, c, "delete_vaults_vaultUuid_items_itemUuid", deleteNote(f, true))
+				checkToolError(t, result, "declined", 0)
+				if !noteExists(f) {
+					t.Error("declined delete removed the item")
+				}
+			})
+
+			t.Run("cleared field", func(t *testing.T) {
+				f := newFixture(t)
+				c := newConfirmingClient(t, f)
+				args := map[string]any{"vaultUuid": f.vault.Id, "itemUuid": f.login.Id, "field": "password", "value": ""}
+				result := callTool(t, c, "set_item_field", args)
+				checkToolError(t, result, "confirmation_required", 0)
+				if !strings.Contains(text(result), `"Database"`) || strings.Contains(text(result), "hunter2") {
+					t.Errorf("confirmation = %s, want the item named and no value", text(result))
+				}
+				args["confirm"] = true
+				succeeded(t, callTool(t, c, "set_item_field", args))
+				// Setting a value replaces nothing that needs confirming.
+				succeeded(t, callTool(t, c, "set_item_field", map[string]any{"vaultUuid": f.vault.Id, "itemUuid": f.login.Id, "field": "password", "value": "hunter3"}))
+			})
+
+			t.Run("patch removals", func(t *testing.T) {
+				f := newFixture(t)
+				c := newConfirmingClient(t, f)
+				patches := map[string]struct {
+					patch   []any
+					confirm bool
+				}{
+					"remove field":     {patch: []any{map[string]any{"op": "remove", "path": "/fields/pin"}}, confirm: true},
+					"fewer tags":       {patch: []any{map[string]any{"op": "replace", "path": "/tags", "value": []any{}}}, confirm: true},
+					"fewer sections":   {patch: []any{map[string]any{"op": "replace", "path": "/sections", "value": []any{}}}, confirm: true},
+					"cleared value":    {patch: []any{map[string]any{"op": "replace", "path": "/fields/password/value", "value": ""}}, confirm: true},
+					"cleared field":    {patch: []any{map[string]any{"op": "replace", "path": "/fields/username", "value": map[string]any{"id": "username", "type": "STRING"}}}, confirm: true},
+					"more tags":        {patch: []any{map[string]any{"op": "replace", "path": "/tags", "value": []any{"db", "prod"}}}},
+					"changed value":    {patch: []any{map[string]any{"op": "replace", "path": "/fields/password/value", "value": "hunter3"}}},
+					"generated value":  {patch: []any{map[string]any{"op": "replace", "path": "/fields/password", "value": map[string]any{"id": "password", "type": "CONCEALED", "generate": true}}}},
+					"renamed the item": {patch: []any{map[string]any{"op": "replace", "path": "/title", "value": "Primary database"}}},
+				}
+				for name, tc := range patches {
+					result := callTool(t, c, "patch_vaults_vaultUuid_items_itemUuid", map[string]any{
+						"vaultUuid": f.vault.Id, "itemUuid": f.login.Id, "items": tc.patch,
+					})
+					if tc.confirm {
+						checkToolError(t, result, "confirmation_required", 0)
+					} else if result.IsError {
+						t.Errorf("%s: %s", name, text(result))
+					}
+				}
+			})
+		})
+	}
+}
+
+// TestSetItemField checks that set_item_field takes exactly one of a value
+// and generate, and that an empty value clears the field.
+func TestSetItemField(t *testing.T) {
+	for name, newClient := range transports {
+		t.Run(name, func(t *testing.T) {
+			f := newFixture(t)
+			c := newClient(t, f.connect.APIConfig())
+			args := func(extra map[string]any) map[string]any {
+				args := map[string]any{"vaultUuid": f.vault.Id, "itemUuid": f.login.Id, "field": "username"}
+				maps.Copy(args, extra)
+				return args
+			}
+
+			for _, extra := range []map[string]any{nil, {"value": "root", "generate": true}, {"value": "", "generate": true}} {
+				result := callTool(t, c, "set_item_field", args(extra))
+				if !result.IsError || !strings.Contains(text(result), "Pass either a value or generate: true") {
+					t.Errorf("set_item_field with %v: %s", extra, text(result))
+				}
+			}
+
+			result := callTool(t, c, "set_item_field", args(map[string]any{"value": ""}))
+			succeeded(t, result)
+			stored, _ := f.connect.Store.Item(f.vault.Id, f.login.Id)
+			for _, field := range stored.Fields {
+				if field.Id == "username" && field.Value != "" {
+					t.Errorf("cleared username = %q", field.Value)
+				}
+			}
+			if stored.Version != f.login.Version+1 {
+				t.Errorf("version = %d, want %d", stored.Version, f.login.Version+1)
+			}
+
+			// Clearing a field that is already empty sends no patch.
+			before := len(f.connect.Requests())
+			succeeded(t, callTool(t, c, "set_item_field", args(map[string]any{"value": ""})))
+			for _, r := range f.connect.Requests()[before:] {
+				if r.Method == http.MethodPatch {
+					t.Errorf("clearing an empty field sent %s %s", r.Method, r.Path)
+				}
+			}
+		})
+	}
+}
+
+// TestHTTPSessions checks that Streamable HTTP sessions keep the credentials
+// they were initialized with, and that expired or terminated sessions are
+// refused with 404.
+func TestHTTPSessions(t *testing.T) {
+	f := newFixture(t)
+	other := connecttest.NewServer()
+	t.Cleanup(other.Close)
+	other.Store.AddVault("Elsewhere", "")
+
+	serverCfg := &config.APIConfig{}
+	mcpSrv, _ := createMCPServer(serverCfg, "HTTP", &server.Hooks{})
+	const ttl = 300 * time.Millisecond
+	httpSrv := httptest.NewServer(streamableHandler(mcpSrv, serverCfg, session.NewManager(ttl)))
+	t.Cleanup(httpSrv.Close)
+
+	credentials := func(cfg *config.APIConfig) map[string]string {
+		return map[string]string{"API_BASE_URL": cfg.BaseURL, "BEARER_TOKEN": cfg.BearerToken}
+	}
+	send := func(method, sessionID string, headers map[string]string, message string) *http.Response {
+		t.Helper()
+		r, err := http.NewRequest(method, httpSrv.URL, strings.NewReader(message))
+		if err != nil {
+			t.Fatal(err)
+		}
+		r.Header.Set("Content-Type", "application/json")
+		r.Header.Set("Accept", "application/json, text/event-stream")
+		if sessionID != "" {
+			r.Header.Set(server.HeaderKeySessionID, sessionID)
+		}
+		for k, v := range headers {
+			r.Header.Set(k, v)
+		}
+		resp, err := http.DefaultClient.Do(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+	initialize := func() string {
+		t.Helper()
+		resp := send(http.MethodPost, "", credentials(f.connect.APIConfig()),
+			`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"`+mcp.LATEST_PROTOCOL_VERSION+`","capabilities":{},"clientInfo":{"name":"tools_test","version":"1.0.0"}}}`)
+		sessionID := resp.Header.Get(server.HeaderKeySessionID)
+		if resp.StatusCode != http.StatusOK || sessionID == "" {
+			t.Fatalf("initialize = %s with session %q", resp.Status, sessionID)
+		}
+		send(http.MethodPost, sessionID, nil, `{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+		return sessionID
+	}
+	const listVaults = `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"get_vaults","arguments":{}}}`
+
+	t.Run("bound credentials", func(t *testing.T) {
+		sessionID := initialize()
+		// Headers of later requests cannot move the session to another
+		// Connect server or token.
+		resp := send(http.MethodPost, sessionID, credentials(other.APIConfig()), listVaults)
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != http.StatusOK || !strings.Contains(string(body), "Production") || strings.Contains(string(body), "Elsewhere") {
+			t.Errorf("get_vaults = %s: %s, want the vaults of the initialized credentials", resp.Status, body)
+		}
+		for _, r := range other.Requests() {
+			t.Errorf("other Connect server received %s %s", r.Method, r.Path)
+		}
+	})
+
+	t.Run("terminated", func(t *testing.T) {
+		sessionID := initialize()
+		if resp := send(http.MethodDelete, sessionID, nil, ""); resp.StatusCode != http.StatusOK {
+			t.Fatalf("DELETE = %s", resp.Status)
+		}
+		if resp := send(http.MethodPost, sessionID, credentials(f.connect.APIConfig()), listVaults); resp.StatusCode != http.StatusNotFound {
+			t.Errorf("request in terminated session = %s, want 404", resp.Status)
+		}
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		sessionID := initialize()
+		time.Sleep(2 * ttl)
+		if resp := send(http.MethodPost, sessionID, credentials(f.connect.APIConfig()), listVaults); resp.StatusCode != http.StatusNotFound {
+			t.Errorf("request in expired session = %s, want 404", resp.Status)
+		}
+		if resp := send(http.MethodGet, sessionID, nil, ""); resp.StatusCode != http.StatusNotFound {
+			t.Errorf("GET in expired session = %s, want 404", resp.Status)
+		}
+	})
+}
+
+// TestSSESessions checks that an SSE session keeps the credentials its
+// stream was opened with, whatever later messages send.
+func TestSSESessions(t *testing.T) {
+	f := newFixture(t)
+	other := connecttest.NewServer()
+	t.Cleanup(other.Close)
+	other.Store.AddVault("Elsewhere", "")
+	url := serveSSE(t, f.connect.APIConfig())
+
+	resp, err := http.Get(url + "/sse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("GET /sse without credentials = %s, want 400", resp.Status)
+	}
+
+	var headers atomic.Pointer[map[string]string]
+	headers.Store(&map[string]string{"API_BASE_URL": f.connect.APIConfig().BaseURL, "BEARER_TOKEN": f.connect.APIConfig().BearerToken})
+	trans, err := transport.NewSSE(url+"/sse", transport.WithHeaderFunc(func(context.Context) map[string]string { return *headers.Load() }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := startClient(t, mcpclient.NewClient(trans))
+	headers.Store(&map[string]string{"API_BASE_URL": other.APIConfig().BaseURL, "BEARER_TOKEN": other.APIConfig().BearerToken})
+
+	result := callTool(t, c, "get_vaults", nil)
+	succeeded(t, result)
+	if !strings.Contains(text(result), "Production") || strings.Contains(text(result), "Elsewhere") {
+		t.Errorf("get_vaults = %s, want the vaults of the stream's credentials", text(result))
+	}
+	for _, r := range other.Requests() {
+		t.Errorf("other Connect server received %s %s", r.Method, r.Path)
+	}
+}
+
+// TestDownloadFile checks how file contents come back and which downloads
+// are refused.
+func TestDownloadFile(t *testing.T) {
+	const tool = "get_vaults_vaultUuid_items_itemUuid_files_fileUuid_content"
+	download := func(f *fixture, file models.File, reveal bool) map[string]any {
+		return map[string]any{"vaultUuid": f.vault.Id, "itemUuid": f.login.Id, "fileUuid": file.Id, "reveal": reveal}
+	}
+	for name, newClient := range transports {
+		t.Run(name, func(t *testing.T) {
+			t.Run("binary", func(t *testing.T) {
+				f := newFixture(t)
+				result := callTool(t, newClient(t, f.connect.APIConfig()), tool, download(f, f.binary, true))
+				succeeded(t, result)
+				var blob *mcp.BlobResourceContents
+				for _, content := range result.Content {
+					if resource, ok := mcp.AsEmbeddedResource(content); ok {
+						blob, _ = mcp.AsBlobResourceContents(resource.Resource)
+					}
+				}
+				if blob == nil {
+					t.Fatalf("content = %+v, want a blob resource", result.Content)
+				}
+				if data, err := base64.StdEncoding.DecodeString(blob.Blob); err != nil || string(data) != "\x00\xff\x10\x80" {
+					t.Errorf("blob = %q, %v", data, err)
+				}
+				if !strings.HasSuffix(blob.URI, "/files/"+f.binary.Id+"/content") || blob.MIMEType == "" {
+					t.Errorf("blob URI = %q, MIME type = %q", blob.URI, blob.MIMEType)
+				}
+			})
+
+			t.Run("redacted", func(t *testing.T) {
+				f := newFixture(t)
+				result := callTool(t, newClient(t, f.connect.APIConfig()), tool, download(f, f.text, false))
+				checkToolError(t, result, "redacted", 0)
+				if strings.Contains(text(result), "db.internal") {
+					t.Errorf("refusal shows the content: %s", text(result))
+				}
+			})
+
+			t.Run("too large", func(t *testing.T) {
+				f := newFixture(t)
+				cfg := f.connect.APIConfig()
+				cfg.MaxDownloadSize = 8
+				result := callTool(t, newClient(t, cfg), tool, download(f, f.text, true))
+				checkToolError(t, result, "too_large", 0)
+				if !strings.Contains(text(result), "File is 17 bytes") {
+					t.Errorf("error = %s, want the announced size", text(result))
+				}
+			})
+
+			t.Run("too large without Content-Length", func(t *testing.T) {
+				fake := connecttest.NewUnstartedServer()
+				fake.Config.Handler = withoutContentLength(fake.Config.Handler)
+				fake.Start()
+				f := seedFixture(t, fake)
+				cfg := f.connect.APIConfig()
+				cfg.MaxDownloadSize = 8
+				result := callTool(t, newClient(t, cfg), tool, download(f, f.text, true))
+				checkToolError(t, result, "too_large", 0)
+
+				// A file within the limit is read in full.
+				cfg.MaxDownloadSize = 17
+				result = callTool(t, newClient(t, cfg), tool, download(f, f.text, true))
+				succeeded(t, result)
+				if text(result) != "host=db.internal\n" {
+					t.Errorf("content = %q", text(result))
+				}
+			})
+		})
+	}
+}
+
+// withoutContentLength streams every response of h in chunks, without the
+// Content-Length header h set.
+func withoutContentLength(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.ServeHTTP(chunkedWriter{w}, r)
+	})
+}
+
+type chunkedWriter struct{ http.ResponseWriter }
+
+func (w chunkedWriter) WriteHeader(status int) {
+	w.Header().Del("Content-Length")
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Write flushes each write, so that the server cannot count the body and
+// set Content-Length itself.
+func (w chunkedWriter) Write(b []byte) (int, error) {
+	w.Header().Del("Content-Length")
+	n, err := w.ResponseWriter.Write(b)
+	w.ResponseWriter.(http.Flusher).Flush()
+	return n, err
+}
+
+// TestResolveNames checks the errors tools return for vault and item names
+// that match nothing or several things.
+func TestResolveNames(t *testing.T) {
+	for name, newClient := range transports {
+		t.Run(name, func(t *testing.T) {
+			f := newFixture(t)
+			duplicate, err := f.connect.Store.AddItem(f.vault.Id, models.FullItem{Title: "database", Category: "LOGIN"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			c := newClient(t, f.connect.APIConfig())
+			getItem := func(vault, item string) *mcp.CallToolResult {
+				return callTool(t, c, "get_vaults_vaultUuid_items_itemUuid", map[string]any{"vaultUuid": vault, "itemUuid": item})
+			}
+
+			result := getItem("Production", "DATABASE")
+			checkToolError(t, result, "ambiguous", 0)
+			var out struct {
+				Error struct{ Candidates []connect.Candidate }
+			}
+			structured(t, result, &out)
+			ids := []string{}
+			for _, candidate := range out.Error.Candidates {
+				ids = append(ids, candidate.ID)
+			}
+			slices.Sort(ids)
+			want := []string{f.login.Id, duplicate.Id}
+			slices.Sort(want)
+			if !slices.Equal(ids, want) {
+				t.Errorf("candidates = %+v, want both items", out.Error.Candidates)
+			}
+
+			// An exact match among them still wins.
+			succeeded(t, getItem("Production", "database"))
+			checkToolError(t, getItem("Production", "Cache"), "not_found", 0)
+			checkToolError(t, getItem("Development", "Database"), "not_found", 0)
+			checkToolError(t, getItem(f.vault.Id, "zzzzzzzzzzzzzzzzzzzzzzzzzz"), "not_found", http.StatusNotFound)
+		})
+	}
+}
+
+// TestFaults checks how tools surface the failures the fake injects.
+func TestFaults(t *testing.T) {
+	for name, newClient := range transports {
+		t.Run(name, func(t *testing.T) {
+			t.Run("retried 5xx", func(t *testing.T) {
+				f := newFixture(t)
+				c := newClient(t, f.connect.APIConfig())
+				before := len(f.connect.Requests())
+				f.connect.Inject(connecttest.Fault{Path: "/vaults", Status: http.StatusServiceUnavailable, Times: 2})
+				result := callTool(t, c, "get_vaults", nil)
+				succeeded(t, result)
+				if n := len(f.connect.Requests()) - before; n != 3 {
+					t.Errorf("Connect received %d requests, want 3", n)
+				}
+				var attempts any
+				if result.Meta != nil {
+					attempts = result.Meta.AdditionalFields[attemptsMetaKey]
+				}
+				want := map[string]any{"requests": float64(3), "retries": float64(2)}
+				if !reflect.DeepEqual(attempts, want) {
+					t.Errorf("_meta attempts = %v, want %v", attempts, want)
+				}
+			})
+
+			t.Run("persistent 5xx", func(t *testing.T) {
+				f := newFixture(t)
+				c := newClient(t, f.connect.APIConfig())
+				f.connect.Inject(connecttest.Fault{Path: "/vaults", Status: http.StatusBadGateway})
+				result := callTool(t, c, "get_vaults", nil)
+				checkToolError(t, result, "server_error", http.StatusBadGateway)
+				var detail struct {
+					Error struct{ Attempts int }
+				}
+				structured(t, result, &detail)
+				if detail.Error.Attempts != 3 {
+					t.Errorf("error attempts = %d, want 3", detail.Error.Attempts)
+				}
+			})
+
+			t.Run("401", func(t *testing.T) {
+				f := newFixture(t)
+				cfg := f.connect.APIConfig()
+				cfg.BearerToken = "revoked"
+				result := callTool(t, newClient(t, cfg), "get_vaults_vaultUuid", map[string]any{"vaultUuid": f.vault.Id})
+				checkToolError(t, result, "unauthorized", http.StatusUnauthorized)
+				activity := f.connect.Store.Activity()
+				if last := activity[len(activity)-1]; last.Result != "DENY" {
+					t.Errorf("last activity = %+v, want a denied request", last)
+				}
+			})
+
+			t.Run("413", func(t *testing.T) {
+				f := newFixture(t)
+				f.connect.Store.InlineLimit = 8
+				result := callTool(t, newClient(t, f.connect.APIConfig()), "get_vaults_vaultUuid_items_itemUuid_files", map[string]any{
+					"vaultUuid": f.vault.Id, "itemUuid": f.login.Id, "inline_files": true,
+				})
+				checkToolError(t, result, "too_large", http.StatusRequestEntityTooLarge)
+			})
+
+			t.Run("latency", func(t *testing.T) {
+				f := newFixture(t)
+				cfg := f.connect.APIConfig()
+				cfg.RequestTimeout = 50 * time.Millisecond
+				cfg.Retry.MaxAttempts = 1
+				c := newClient(t, cfg)
+				f.connect.Inject(connecttest.Fault{Path: "/vaults", Latency: time.Second})
+				result := callTool(t, c, "get_vaults", nil)
+				checkToolError(t, result, "timeout", 0)
+			})
+
+			t.Run("timeoutMs", func(t *testing.T) {
+				f := newFixture(t)
+				c := newClient(t, f.connect.APIConfig())
+				f.connect.Inject(connecttest.Fault{Path: "/vaults", Latency: 5 * time.Second})
+				request := mcp.CallToolRequest{}
+				request.Params.Name = "get_vaults"
+				request.Params.Arguments = map[string]any{}
+				request.Params.Meta = &mcp.Meta{AdditionalFields: map[string]any{"timeoutMs": 50}}
+				start := time.Now()
+				result, err := c.CallTool(context.Background(), request)
+				if err != nil {
+					t.Fatal(err)
+				}
+				checkToolError(t, result, "timeout", 0)
+				if elapsed := time.Since(start); elapsed > 2*time.Second {
+					t.Errorf("gave up after %v, want about 50ms", elapsed)
+				}
+			})
+		})
+	}
+}
+
+// TestInjectSecretReferences checks which Connect requests
+// inject_secret_references makes.
+func TestInjectSecretReferences(t *testing.T) {
+	// itemRequests counts the item lookups and reads Connect received
+	// since the first n requests.
+	itemRequests := func(f *fixture, n int) (lookups, reads int) {
+		for _, r := range f.connect.Requests()[n:] {
+			switch {
+			case r.Path == connect.Path("vaults", f.vault.Id, "items"):
+				lookups++
+			case strings.HasPrefix(r.Path, connect.Path("vaults", f.vault.Id, "items")+"/"):
+				reads++
+			}
+		}
+		return lookups, reads
+	}
+
+	for name, newClient := range transports {
+		t.Run(name, func(t *testing.T) {
+			t.Run("dry run", func(t *testing.T) {
+				f := newFixture(t)
+				c := newClient(t, f.connect.APIConfig())
+				before := len(f.connect.Requests())
+				result := callTool(t, c, "inject_secret_references", map[string]any{
+					"template": "{{ op://Production/Database/password }} {{ op://Production/Database/username }}",
+					"dryRun":   true,
+				})
+				succeeded(t, result)
+				var out secretref.InjectResult
+				structured(t, result, &out)
+				if len(out.References) != 2 || !strings.Contains(out.Text, "{{ op://Production/Database/password }}") {
+					t.Errorf("result = %+v, want both references listed and left in place", out)
+				}
+				if n := len(f.connect.Requests()) - before; n != 0 {
+					t.Errorf("Connect received %d requests, want none", n)
+				}
+			})
+
+			t.Run("strict", func(t *testing.T) {
+				f := newFixture(t)
+				result := callTool(t, newClient(t, f.connect.APIConfig()), "inject_secret_references", map[string]any{
+					"template": "{{ op://Production/Database/username }} {{ op://Production/Database/missing }}",
+					"strict":   true,
+					"reveal":   true,
+				})
+				if !result.IsError {
+					t.Fatalf("tool call succeeded: %s", text(result))
+				}
+				if got := text(result); !strings.Contains(got, "op://Production/Database/missing") || strings.Contains(got, "postgres") {
+					t.Errorf("error = %q, want the unresolved reference and no values", got)
+				}
+			})
+
+			t.Run("one fetch per item", func(t *testing.T) {
+				f := newFixture(t)
+				before := len(f.connect.Requests())
+				result := callTool(t, newClient(t, f.connect.APIConfig()), "inject_secret_references", map[string]any{
+					"template": "{{ op://Production/Database/username }}:{{ op://Production/Database/password }}@{{ op://Production/Database/username }}",
+					"reveal":   true,
+				})
+				succeeded(t, result)
+				var out secretref.InjectResult
+				structured(t, result, &out)
+				if out.Text != "postgres:hunter2@postgres" {
+					t.Errorf("text = %q", out.Text)
+				}
+				if lookups, reads := itemRequests(f, before); lookups != 1 || reads != 1 {
+					t.Errorf("Connect received %d item lookups and %d item reads, want 1 of each", lookups, reads)
+				}
+			})
+		})
+	}
+}
+
+func checkToolError(t *testing.T, result *mcp.CallToolResult, kind string, status int) {
+	t.Helper()
+	if !result.IsError {
+		t.Fatalf("tool call succeeded: %s", text(result))
+	}
+	var out struct {
+		Error struct {
+			Kind   string
+			Status int
+		}
+	}
+	structured(t, result, &out)
+	if out.Error.Kind != kind || out.Error.Status != status {
+		t.Errorf("error = %+v, want kind %s and status %d: %s", out.Error, kind, status, text(result))
+	}
+}
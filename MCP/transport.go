@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/1password-connect/mcp-server/config"
+	"github.com/1password-connect/mcp-server/policy"
+	"github.com/1password-connect/mcp-server/session"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// streamableHandler serves the Streamable HTTP transport. Requests within a
+// session use the credentials bound at initialize time; the session manager
+// rejects unknown or expired sessions.
+func streamableHandler(mcpSrv *server.MCPServer, cfg *config.APIConfig, sessions *session.Manager) http.Handler {
+	streamable := server.NewStreamableHTTPServer(mcpSrv,
+		server.WithSessionIdManager(sessions),
+		server.WithHeartbeatInterval(30*time.Second),
+		server.WithHTTPContextFunc(func(ctx context.Context, r *http.Request) context.Context {
+			clientSession := server.ClientSessionFromContext(ctx)
+			if clientSession == nil {
+				return ctx
+			}
+			sessionID := clientSession.SessionID()
+			if apiCfg, ok := sessions.Config(sessionID); ok {
+				return config.WithContext(ctx, apiCfg)
+			}
+			// First request of the session: bind the credentials from the
+			// initialize request, which the handler below has validated.
+			apiCfg, err := apiConfigFromHeaders(r, cfg)
+			if err != nil {
+				return ctx
+			}
+			sessions.Bind(sessionID, apiCfg)
+			log.Printf("Initialized session %s - BaseURL: %s", sessionID, apiCfg.BaseURL)
+			return config.WithContext(ctx, apiCfg)
+		}),
+	)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if sessionID := r.Header.Get(server.HeaderKeySessionID); sessionID != "" {
+			if r.Method == http.MethodGet && !sessions.Active(sessionID) {
+				http.Error(w, "Session not found", http.StatusNotFound)
+				return
+			}
+			streamable.ServeHTTP(w, r)
+			return
+		}
+		if r.Method != http.MethodPost {
+			http.Error(w, "Missing "+server.HeaderKeySessionID+" header", http.StatusBadRequest)
+			return
+		}
+		if _, err := apiConfigFromHeaders(r, cfg); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		streamable.ServeHTTP(w, r)
+	})
+}
+
+// sseCredentials binds the credentials sent with a legacy SSE connection to
+// the session the SSE server creates for it, for as long as the stream stays
+// open.
+type sseCredentials struct {
+	sessions sync.Map // session ID -> *config.APIConfig
+}
+
+func newSSECredentials() *sseCredentials {
+	return &sseCredentials{}
+}
+
+// register wires the binding into the MCP server's session hooks. The GET
+// /sse handler puts the validated configuration in the request context, which
+// the SSE server passes to RegisterSession.
+func (c *sseCredentials) register(hooks *server.Hooks) {
+	hooks.AddOnRegisterSession(func(ctx context.Context, clientSession server.ClientSession) {
+		if apiCfg, ok := config.FromContext(ctx); ok {
+			c.sessions.Store(clientSession.SessionID(), apiCfg)
+			log.Printf("Opened SSE session %s - BaseURL: %s", clientSession.SessionID(), apiCfg.BaseURL)
+		}
+	})
+	hooks.AddOnUnregisterSession(func(ctx context.Context, clientSession server.ClientSession) {
+		c.sessions.Delete(clientSession.SessionID())
+	})
+}
+
+// handlers returns the /sse and /message handlers of the SSE transport.
+func (c *sseCredentials) handlers(mcpSrv *server.MCPServer, cfg *config.APIConfig) (sse, message http.Handler) {
+	sseSrv := server.NewSSEServer(mcpSrv,
+		server.WithSSEEndpoint("/sse"),
+		server.WithMessageEndpoint("/message"),
+		server.WithKeepAlive(true),
+		server.WithSSEContextFunc(func(ctx context.Context, r *http.Request) context.Context {
+			if clientSession := server.ClientSessionFromContext(ctx); clientSession != nil {
+				if apiCfg, ok := c.sessions.Load(clientSession.SessionID()); ok {
+					return config.WithContext(ctx, apiCfg.(*config.APIConfig))
+				}
+			}
+			return ctx
+		}),
+	)
+
+	sse = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		apiCfg, err := apiConfigFromHeaders(r, cfg)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		sseSrv.SSEHandler().ServeHTTP(w, r.WithContext(config.WithContext(r.Context(), apiCfg)))
+	})
+	return sse, sseSrv.MessageHandler()
+}
+
+// apiConfigFromHeaders builds the Connect configuration for an HTTP session
+// from the request headers, inheriting server-wide limits from cfg.
+func apiConfigFromHeaders(r *http.Request, cfg *config.APIConfig) (*config.APIConfig, error) {
+	apiCfg := &config.APIConfig{
+		BaseURL:      r.Header.Get("API_BASE_URL"),
+		BearerToken:  r.Header.Get("BEARER_TOKEN"),
+		APIKey:       r.Header.Get("API_KEY"),
+		APIKeyHeader: cfg.APIKeyHeader,
+		BasicAuth:    r.Header.Get("BASIC_AUTH"),
+		AuthType:     strings.ToLower(r.Header.Get("AUTH_TYPE")),
+
+		MaxDownloadSize:    cfg.MaxDownloadSize,
+		RequestTimeout:     cfg.RequestTimeout,
+		Retry:              cfg.Retry,
+		RedactionMode:      cfg.RedactionMode,
+		ReadOnly:           cfg.ReadOnly,
+		ConfirmDestructive: cfg.ConfirmDestructive,
+		Policy:             cfg.Policy,
+	}
+	if apiCfg.BaseURL == "" {
+		return nil, fmt.Errorf("missing API_BASE_URL header")
+	}
+	// A session policy can only narrow the server's.
+	if v := r.Header.Get("POLICY"); v != "" {
+		sessionPolicy, err := policy.Parse([]byte(v))
+		if err != nil {
+			return nil, fmt.Errorf("invalid POLICY header: %w", err)
+		}
+		apiCfg.Policy = sessionPolicy.Within(cfg.Policy)
+	}
+	if _, err := apiCfg.ResolveAuthType(); err != nil {
+		return nil, fmt.Errorf("invalid authentication headers: %w", err)
+	}
+	return apiCfg, nil
+}